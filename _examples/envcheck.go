@@ -11,7 +11,7 @@ import (
 
 // go run ./_examples/envcheck.go
 //
-// bash:
+// show debug logs on detect, bash:
 // 	COLOR_DEBUG_MODE=on go run ./_examples/envcheck.go
 // cmd.exe:
 //   set COLOR_DEBUG_MODE=on
@@ -24,20 +24,9 @@ func main() {
 	fmt.Println("Support 256 Color:", color.Support256Color())
 	fmt.Println("Support True Color:", color.SupportTrueColor())
 
-	fmt.Println("------- Re-Detect by Enable Debug Mode -------")
-	color.EnableDebug()
-	fmt.Println("Detected Color Level:", color.DetectColorLevel())
-
-	if es := color.InnerErrs(); len(es) > 0 {
-		fmt.Println("inner errors:", es)
-	}
-
-	termVal := os.Getenv("TERM")
-	fmt.Println("ENV TERM:", termVal)
-	// fmt.Println(
-	// 	termVal[0:1],
-	// 	strconv.FormatUint(uint64(termVal[0]), 16),
-	// )
+	fmt.Println("------- Detection Report -------")
+	// paste the report to the issue, when the color level is not as expected.
+	fmt.Print(color.DetectReport())
 
 	fmt.Println("------- Test Color Output -------")
 	fmt.Println("\x1b[34mHello \x1b[35mWorld\x1b[0m!")
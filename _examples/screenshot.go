@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gookit/color"
+	"github.com/gookit/color/render"
+)
+
+// go run ./_examples/screenshot.go
+// go run ./_examples/screenshot.go > _examples/images/theme-basic.svg
+func main() {
+	buf := new(bytes.Buffer)
+	r := color.NewRenderer(buf)
+	r.SetLevel(color.LevelRgb)
+	r.SetEnable(true)
+
+	r.Println("Built In Themes(styles):")
+	for _, name := range []string{"info", "note", "light", "error", "danger", "notice", "success", "comment", "primary", "warning", "question", "secondary"} {
+		r.Println(r.Tag(name, name+" message"))
+	}
+
+	fmt.Print(render.SVG(buf.String(), render.SVGOptions{Title: "theme_basic.go"}))
+}
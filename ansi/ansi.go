@@ -0,0 +1,318 @@
+// Package ansi provide a tokenizer for parse the ANSI escape sequences in the text.
+//
+// It supports SGR color codes(16, 256, RGB colors and the colon sub-params),
+// CSI sequences(cursor moves, erase ...), OSC(hyperlinks, window title), DCS and
+// the other control strings. The Raw of all tokens can be joined back to the input.
+//
+// Usage:
+//
+//	for _, tok := range ansi.Tokenize("\x1b[1;32mhello\x1b[0m") {
+//		fmt.Println(tok.Kind, tok.Raw)
+//	}
+//
+//	text := ansi.Strip("\x1b[1;32mhello\x1b[0m") // "hello"
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// control chars
+const (
+	ESC = 0x1b
+	BEL = 0x07
+	// ST string terminator for OSC, DCS
+	ST = "\x1b\\"
+)
+
+// Kind of the token
+type Kind uint8
+
+// token kinds
+const (
+	// KindText plain text, also include the incomplete escape sequences
+	KindText Kind = iota
+	// KindSGR select graphic rendition, the color codes. eg: "\x1b[1;32m"
+	KindSGR
+	// KindCSI other CSI sequences. eg: cursor moves "\x1b[2A", erase line "\x1b[2K"
+	KindCSI
+	// KindOSC operating system command. eg: hyperlink "\x1b]8;;URL\x1b\\", title "\x1b]0;TITLE\a"
+	KindOSC
+	// KindDCS device control string. eg: "\x1bP...\x1b\\"
+	KindDCS
+	// KindAPC application program command, also include the SOS and PM strings.
+	KindAPC
+	// KindEsc other escape sequences. eg: "\x1b7", "\x1b(B"
+	KindEsc
+)
+
+var kindNames = [...]string{"Text", "SGR", "CSI", "OSC", "DCS", "APC", "Esc"}
+
+// String get kind name
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Param a CSI parameter. eg: "4:3" -> Param{Value: 4, Sub: []int{3}}
+//
+// Omitted value is -1. eg: "\x1b[;5H" the first param Value is -1
+type Param struct {
+	Value int
+	// Sub the colon separated sub-params
+	Sub []int
+}
+
+// Int get the param value, returns def if it is omitted.
+func (p Param) Int(def int) int {
+	if p.Value < 0 {
+		return def
+	}
+	return p.Value
+}
+
+// Token a parsed text or escape sequence.
+type Token struct {
+	Kind Kind
+	// Raw text of the token
+	Raw string
+	// Private marker of the CSI params. eg: '?' in "\x1b[?25h"
+	Private byte
+	// Params of the SGR, CSI. eg: "\x1b[38;5;12m" -> 38, 5, 12
+	Params []Param
+	// Inter the intermediate bytes of the CSI, Esc. eg: "(" in "\x1b(B"
+	Inter string
+	// Final byte of the SGR, CSI, Esc. eg: 'm', 'H'
+	Final byte
+	// Cmd of the OSC. eg: 8 for hyperlink. it's -1 if not a number
+	Cmd int
+	// Data of the OSC, DCS, APC, without the OSC command and the terminator.
+	Data string
+}
+
+// String get the raw text of the token
+func (t Token) String() string { return t.Raw }
+
+// Param get the CSI param value by index, returns def if not exists or omitted.
+func (t Token) Param(i, def int) int {
+	if i < len(t.Params) {
+		return t.Params[i].Int(def)
+	}
+	return def
+}
+
+// Tokenize parse the string to tokens.
+func Tokenize(s string) []Token {
+	var toks []Token
+	lx := NewLexer(s)
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		toks = append(toks, tok)
+	}
+	return toks
+}
+
+// Join the raw text of the tokens, it is the reverse of Tokenize.
+func Join(toks []Token) string {
+	var sb strings.Builder
+	for _, tok := range toks {
+		sb.WriteString(tok.Raw)
+	}
+	return sb.String()
+}
+
+// Strip remove all escape sequences in the string, returns the plain text.
+func Strip(s string) string {
+	if strings.IndexByte(s, ESC) < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	lx := NewLexer(s)
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		if tok.Kind == KindText {
+			sb.WriteString(tok.Raw)
+		}
+	}
+	return sb.String()
+}
+
+// Contains check the string contains escape sequences
+func Contains(s string) bool {
+	lx := NewLexer(s)
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		if tok.Kind != KindText {
+			return true
+		}
+	}
+	return false
+}
+
+// Lexer a tokenizer for the ANSI text.
+type Lexer struct {
+	src string
+	pos int
+}
+
+// NewLexer create a lexer for the string
+func NewLexer(s string) *Lexer {
+	return &Lexer{src: s}
+}
+
+// Next get the next token, returns false on end.
+func (lx *Lexer) Next() (tok Token, ok bool) {
+	if lx.pos >= len(lx.src) {
+		return
+	}
+
+	start := lx.pos
+	if lx.src[start] == ESC {
+		if tok, ok = lx.lexEscape(start); ok {
+			lx.pos = start + len(tok.Raw)
+			return tok, true
+		}
+		// incomplete sequence, the ESC as text
+		start++
+	}
+
+	end := strings.IndexByte(lx.src[start:], ESC)
+	if end < 0 {
+		end = len(lx.src)
+	} else {
+		end += start
+	}
+
+	tok = Token{Kind: KindText, Raw: lx.src[lx.pos:end]}
+	lx.pos = end
+	return tok, true
+}
+
+// lex the escape sequence at src[start], returns false if it is incomplete or invalid.
+func (lx *Lexer) lexEscape(start int) (Token, bool) {
+	s := lx.src
+	i := start + 1
+	if i >= len(s) {
+		return Token{}, false
+	}
+
+	switch s[i] {
+	case '[':
+		return lexCSI(s, start)
+	case ']':
+		return lexString(s, start, KindOSC, true)
+	case 'P':
+		return lexString(s, start, KindDCS, false)
+	case 'X', '^', '_':
+		return lexString(s, start, KindAPC, false)
+	}
+
+	// ESC [0x20-0x2F]* [0x30-0x7E]
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c <= 0x2F {
+			continue
+		}
+
+		if c >= 0x30 && c <= 0x7E {
+			return Token{Kind: KindEsc, Raw: s[start : i+1], Inter: s[start+1 : i], Final: c}, true
+		}
+		break
+	}
+	return Token{}, false
+}
+
+// lex the CSI sequence: ESC [ params(0x30-0x3F)* inter(0x20-0x2F)* final(0x40-0x7E)
+func lexCSI(s string, start int) (Token, bool) {
+	i := start + 2
+	pStart := i
+	for i < len(s) && s[i] >= 0x30 && s[i] <= 0x3F {
+		i++
+	}
+	pEnd := i
+
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2F {
+		i++
+	}
+	if i >= len(s) || s[i] < 0x40 || s[i] > 0x7E {
+		return Token{}, false
+	}
+
+	tok := Token{Kind: KindCSI, Raw: s[start : i+1], Inter: s[pEnd:i], Final: s[i]}
+	params := s[pStart:pEnd]
+	if params != "" && strings.IndexByte("<=>?", params[0]) >= 0 {
+		tok.Private = params[0]
+		params = params[1:]
+	}
+
+	tok.Params = parseParams(params)
+	if tok.Final == 'm' && tok.Private == 0 && tok.Inter == "" {
+		tok.Kind = KindSGR
+	}
+	return tok, true
+}
+
+// parse the CSI params. eg: "38;5;12" "4:3;58:2::255:0:0"
+func parseParams(s string) []Param {
+	if s == "" {
+		return nil
+	}
+
+	ss := strings.Split(s, ";")
+	params := make([]Param, 0, len(ss))
+	for _, str := range ss {
+		nodes := strings.Split(str, ":")
+		p := Param{Value: parseParamInt(nodes[0])}
+		if len(nodes) > 1 {
+			p.Sub = make([]int, 0, len(nodes)-1)
+			for _, node := range nodes[1:] {
+				p.Sub = append(p.Sub, parseParamInt(node))
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// parse the param value, returns -1 if it is omitted or invalid.
+func parseParamInt(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// lex the control string. terminated by ST, the OSC also can be terminated by BEL.
+func lexString(s string, start int, kind Kind, isOSC bool) (Token, bool) {
+	dStart := start + 2
+	for i := dStart; i < len(s); i++ {
+		var end int
+		if s[i] == BEL && isOSC {
+			end = i + 1
+		} else if s[i] == ESC && i+1 < len(s) && s[i+1] == '\\' {
+			end = i + 2
+		} else {
+			continue
+		}
+
+		tok := Token{Kind: kind, Raw: s[start:end], Data: s[dStart:i], Cmd: -1}
+		if isOSC {
+			cmd := tok.Data
+			if j := strings.IndexByte(cmd, ';'); j >= 0 {
+				cmd, tok.Data = cmd[:j], cmd[j+1:]
+			} else {
+				tok.Data = ""
+			}
+			tok.Cmd = parseParamInt(cmd)
+			if tok.Cmd < 0 { // not a number, keep raw data
+				tok.Data = s[dStart:i]
+			}
+		}
+		return tok, true
+	}
+	return Token{}, false
+}
@@ -0,0 +1,145 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/gookit/assert"
+	"github.com/gookit/color/ansi"
+)
+
+func TestTokenize(t *testing.T) {
+	is := assert.New(t)
+
+	str := "a\x1b[1;32mb\x1b[2Kc\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\\x1b]0;title\a\x1bPq#0\x1b\\\x1b7\x1b(B\x1b_apc\x1b\\d"
+	toks := ansi.Tokenize(str)
+	is.Eq(str, ansi.Join(toks))
+
+	var kinds []ansi.Kind
+	for _, tok := range toks {
+		kinds = append(kinds, tok.Kind)
+	}
+	is.Eq([]ansi.Kind{
+		ansi.KindText, ansi.KindSGR, ansi.KindText, ansi.KindCSI, ansi.KindText,
+		ansi.KindOSC, ansi.KindText, ansi.KindOSC, ansi.KindOSC, ansi.KindDCS,
+		ansi.KindEsc, ansi.KindEsc, ansi.KindAPC, ansi.KindText,
+	}, kinds)
+
+	// CSI
+	is.Eq(byte('K'), toks[3].Final)
+	is.Eq(2, toks[3].Param(0, 0))
+	// OSC
+	is.Eq(8, toks[5].Cmd)
+	is.Eq(";https://example.com", toks[5].Data)
+	is.Eq(0, toks[8].Cmd)
+	is.Eq("title", toks[8].Data)
+	is.Eq("\x1b]0;title\a", toks[8].String())
+	// DCS, Esc
+	is.Eq("q#0", toks[9].Data)
+	is.Eq(byte('7'), toks[10].Final)
+	is.Eq("(", toks[11].Inter)
+	is.Eq(byte('B'), toks[11].Final)
+	is.Eq("SGR", ansi.KindSGR.String())
+}
+
+func TestTokenize_CSI(t *testing.T) {
+	is := assert.New(t)
+
+	tok := ansi.Tokenize("\x1b[?25h")[0]
+	is.Eq(ansi.KindCSI, tok.Kind)
+	is.Eq(byte('?'), tok.Private)
+	is.Eq(25, tok.Param(0, 0))
+
+	tok = ansi.Tokenize("\x1b[;5H")[0]
+	is.Eq(ansi.KindCSI, tok.Kind)
+	is.Eq(1, tok.Param(0, 1))
+	is.Eq(5, tok.Param(1, 1))
+	is.Eq(1, tok.Param(2, 1))
+
+	tok = ansi.Tokenize("\x1b[4:3;58:2::255:0:0m")[0]
+	is.Eq(ansi.KindSGR, tok.Kind)
+	is.Eq([]ansi.Param{{Value: 4, Sub: []int{3}}, {Value: 58, Sub: []int{2, -1, 255, 0, 0}}}, tok.Params)
+
+	// SGR with intermediate is not SGR
+	tok = ansi.Tokenize("\x1b[1 m")[0]
+	is.Eq(ansi.KindCSI, tok.Kind)
+	is.Eq(" ", tok.Inter)
+}
+
+func TestTokenize_incomplete(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []string{"a\x1b", "a\x1b[1;3", "a\x1b]0;title", "a\x1b[1\n2m", "a\x1bPdata\x1b"}
+	for _, str := range tests {
+		toks := ansi.Tokenize(str)
+		is.Eq(str, ansi.Join(toks), str)
+		for _, tok := range toks {
+			is.Eq(ansi.KindText, tok.Kind, str)
+		}
+		is.Eq(str, ansi.Strip(str), str)
+		is.False(ansi.Contains(str), str)
+	}
+
+	// the incomplete ESC is text, the next sequence is parsed
+	toks := ansi.Tokenize("\x1b\x1b[0m")
+	is.Len(toks, 2)
+	is.Eq("\x1b", toks[0].Raw)
+	is.Eq(ansi.KindSGR, toks[1].Kind)
+}
+
+func TestStrip(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("Text", ansi.Strip("Text"))
+	is.Eq("Text", ansi.Strip("\x1b[36;1mText\x1b[0m"))
+	is.Eq("Text", ansi.Strip("\x1b[38;2;30;144;255mText"))
+	is.Eq("ab", ansi.Strip("a\x1b[2K\x1b[1Ab"))
+	is.Eq("link", ansi.Strip("\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"))
+	is.Eq("ab", ansi.Strip("a\x1b]0;title\ab"))
+	is.True(ansi.Contains("a\x1b[m"))
+	is.False(ansi.Contains("a b"))
+}
+
+func TestToken_SGR(t *testing.T) {
+	is := assert.New(t)
+
+	sgr := func(s string) []ansi.Attr {
+		return ansi.Tokenize(s)[0].SGR()
+	}
+
+	is.Nil(ansi.Tokenize("\x1b[2K")[0].SGR())
+	is.Eq([]ansi.Attr{{Code: 0}}, sgr("\x1b[m"))
+
+	attrs := sgr("\x1b[1;31;102m")
+	is.Len(attrs, 3)
+	is.Eq(1, attrs[0].Code)
+	is.Eq(ansi.Color{Type: ansi.ColorBasic, Index: 1}, attrs[1].Color)
+	is.True(attrs[1].IsFg())
+	is.Eq(ansi.Color{Type: ansi.ColorBasic, Index: 10}, attrs[2].Color)
+	is.True(attrs[2].IsBg())
+
+	attrs = sgr("\x1b[38;5;208;48;2;1;2;3;4m")
+	is.Len(attrs, 3)
+	is.Eq(38, attrs[0].Code)
+	is.Eq(ansi.Color{Type: ansi.Color256, Index: 208}, attrs[0].Color)
+	is.Eq(48, attrs[1].Code)
+	is.Eq(ansi.Color{Type: ansi.ColorRGB, R: 1, G: 2, B: 3}, attrs[1].Color)
+	is.Eq(4, attrs[2].Code)
+
+	// unknown or truncated subtype, only the subtype is used
+	attrs = sgr("\x1b[38;9;1m")
+	is.Len(attrs, 2)
+	is.Eq(ansi.Color{}, attrs[0].Color)
+	is.Eq(1, attrs[1].Code)
+	attrs = sgr("\x1b[48;5m")
+	is.Len(attrs, 1)
+	is.Eq(48, attrs[0].Code)
+
+	// colon sub-params
+	attrs = sgr("\x1b[38:5:12;4:3;58:2::255:0:0;48:2:1:2:3m")
+	is.Len(attrs, 4)
+	is.Eq(ansi.Color{Type: ansi.Color256, Index: 12}, attrs[0].Color)
+	is.Eq([]int{3}, attrs[1].Sub)
+	is.Eq(58, attrs[2].Code)
+	is.Eq(ansi.Color{Type: ansi.ColorRGB, R: 255}, attrs[2].Color)
+	is.Eq(ansi.Color{Type: ansi.ColorRGB, R: 1, G: 2, B: 3}, attrs[3].Color)
+}
@@ -0,0 +1,127 @@
+package ansi
+
+// ColorType of the SGR color
+type ColorType uint8
+
+// color types
+const (
+	// ColorNone not a color
+	ColorNone ColorType = iota
+	// ColorBasic the 16 basic colors. Index is 0 - 15
+	ColorBasic
+	// Color256 the 256 colors. Index is 0 - 255
+	Color256
+	// ColorRGB the true color. see R, G, B
+	ColorRGB
+)
+
+// Color a color value of the SGR attribute.
+type Color struct {
+	Type  ColorType
+	Index uint8
+	R     uint8
+	G     uint8
+	B     uint8
+}
+
+// Attr a SGR attribute.
+//
+// eg: "1" -> Attr{Code: 1}, "4:3" -> Attr{Code: 4, Sub: []int{3}},
+// "38;5;12" -> Attr{Code: 38, Color: Color{Type: Color256, Index: 12}}
+type Attr struct {
+	// Code the SGR code. eg: 1, 31, 38, 48, 58
+	Code int
+	// Sub the colon separated sub-params. eg: "4:3" -> [3]
+	Sub []int
+	// Color value for the color codes. eg: 31, 91, 38, 48, 58
+	Color Color
+}
+
+// IsFg check is foreground color. 30-37, 38, 90-97
+func (a Attr) IsFg() bool {
+	return a.Code >= 30 && a.Code <= 38 || a.Code >= 90 && a.Code <= 97
+}
+
+// IsBg check is background color. 40-47, 48, 100-107
+func (a Attr) IsBg() bool {
+	return a.Code >= 40 && a.Code <= 48 || a.Code >= 100 && a.Code <= 107
+}
+
+// SGR decode the SGR params to attributes. returns nil if the token is not SGR.
+//
+// The empty params "\x1b[m" is same as reset: Attr{Code: 0}.
+func (t Token) SGR() []Attr {
+	if t.Kind != KindSGR {
+		return nil
+	}
+
+	if len(t.Params) == 0 {
+		return []Attr{{Code: 0}}
+	}
+
+	attrs := make([]Attr, 0, len(t.Params))
+	for i := 0; i < len(t.Params); i++ {
+		p := t.Params[i]
+		a := Attr{Code: p.Int(0), Sub: p.Sub}
+
+		switch c := a.Code; {
+		case c >= 30 && c <= 37, c >= 40 && c <= 47:
+			a.Color = Color{Type: ColorBasic, Index: uint8(c % 10)}
+		case c >= 90 && c <= 97, c >= 100 && c <= 107:
+			a.Color = Color{Type: ColorBasic, Index: uint8(c%10 + 8)}
+		case c == 38 || c == 48 || c == 58:
+			if len(p.Sub) > 0 { // "38:5:12" "38:2::r:g:b" "38:2:r:g:b"
+				a.Color = extColor(p.Sub)
+			} else { // "38;5;12" "38;2;r;g;b"
+				var n int
+				a.Color, n = extColorParams(t.Params[i+1:])
+				i += n
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// parse extended color from the sub-params. eg: [5 12], [2 r g b], [2 colorSpace r g b]
+func extColor(sub []int) Color {
+	switch {
+	case sub[0] == 5 && len(sub) > 1:
+		return Color{Type: Color256, Index: toUint8(sub[1])}
+	case sub[0] == 2 && len(sub) > 4: // with color space ID
+		return Color{Type: ColorRGB, R: toUint8(sub[2]), G: toUint8(sub[3]), B: toUint8(sub[4])}
+	case sub[0] == 2 && len(sub) > 3:
+		return Color{Type: ColorRGB, R: toUint8(sub[1]), G: toUint8(sub[2]), B: toUint8(sub[3])}
+	}
+	return Color{}
+}
+
+// parse extended color from the next params, returns the number of params used.
+// for an unknown or truncated subtype, only the subtype is used. eg: "38;9;1" keeps the bold.
+func extColorParams(ps []Param) (Color, int) {
+	if len(ps) == 0 {
+		return Color{}, 0
+	}
+
+	switch ps[0].Int(0) {
+	case 5:
+		if len(ps) > 1 {
+			return Color{Type: Color256, Index: toUint8(ps[1].Int(0))}, 2
+		}
+	case 2:
+		if len(ps) > 3 {
+			return Color{Type: ColorRGB, R: toUint8(ps[1].Int(0)), G: toUint8(ps[2].Int(0)), B: toUint8(ps[3].Int(0))}, 4
+		}
+	}
+	return Color{}, 1
+}
+
+func toUint8(n int) uint8 {
+	if n < 0 {
+		return 0
+	}
+	if n > 255 {
+		return 255
+	}
+	return uint8(n)
+}
@@ -11,11 +11,10 @@ For more usage, please see README and tests.
 package color
 
 import (
-	"fmt"
 	"io"
 	"os"
-	"regexp"
-	"strings"
+
+	"github.com/gookit/color/ansi"
 )
 
 // color render templates
@@ -37,6 +36,8 @@ const (
 )
 
 // CodeExpr regex to clear color codes eg "\033[1;36mText\x1b[0m"
+//
+// Deprecated: ClearCode is not use it anymore, it uses the ansi.Strip()
 const CodeExpr = `\033\[[\d;?]+m`
 
 var (
@@ -59,26 +60,32 @@ var (
 	debugMode = os.Getenv("COLOR_DEBUG_MODE") == "on"
 	// inner errors record on detect color level
 	innerErrs []error
-	// output the default io.Writer message print
-	output io.Writer = os.Stdout
 	// the color support level for current terminal
 	// needVTP - need enable VTP, only for Windows OS
 	colorLevel, needVTP = detectTermColorLevel()
-	// match color codes
-	codeRegex = regexp.MustCompile(CodeExpr)
+	// supportLink the terminal supports OSC 8 hyperlinks
+	supportLink = detectLinkSupport()
+	// std the default renderer, output to os.Stdout
+	std = newStdRenderer(colorLevel, os.Stdout)
 )
 
+// Std get the default renderer. the package-level functions are delegate to it.
+func Std() *Renderer { return std }
+
 // TermColorLevel Get the currently supported color level
-func TermColorLevel() Level { return colorLevel }
+func TermColorLevel() Level { return std.level }
 
 // SupportColor Whether the current environment supports color output
-func SupportColor() bool { return colorLevel > LevelNo }
+func SupportColor() bool { return std.level > LevelNo }
 
 // Support256Color Whether the current environment supports 256-color output
-func Support256Color() bool { return colorLevel > Level16 }
+func Support256Color() bool { return std.level > Level16 }
 
 // SupportTrueColor Whether the current environment supports (RGB)True-color output
-func SupportTrueColor() bool { return colorLevel > Level256 }
+func SupportTrueColor() bool { return std.level > Level256 }
+
+// SupportLink Whether the current environment supports OSC 8 hyperlinks. see Link()
+func SupportLink() bool { return std.link }
 
 /*************************************************************
  * global settings
@@ -108,24 +115,35 @@ func Disable() bool {
 func NotRenderTag() { RenderTag = false }
 
 // SetOutput set default colored text output
-func SetOutput(w io.Writer) { output = w }
+func SetOutput(w io.Writer) { std.output = w }
+
+// SetDetectWriter set detect the writer on print messages.
+//
+// If enabled, the Print*, Fprint* functions will check the writer is a terminal,
+// if not(eg: bytes.Buffer, pipe, file), will strip color codes and tags on output.
+//
+// Usage:
+//
+//	color.SetDetectWriter(true)
+//	color.Fprint(logFile, "<info>message</>") // output: message
+func SetDetectWriter(enable bool) { std.SetDetectWriter(enable) }
 
 // ResetOutput reset output
-func ResetOutput() { output = os.Stdout }
+func ResetOutput() { std.output = os.Stdout }
 
 // ResetOptions reset all package option setting
 func ResetOptions() {
 	RenderTag = true
 	Enable = true
-	output = os.Stdout
+	std.output = os.Stdout
+	std.detectWriter = false
 }
 
 // ForceSetColorLevel force open color render
-func ForceSetColorLevel(level Level) Level {
-	oldLevelVal := colorLevel
-	colorLevel = level
-	return oldLevelVal
-}
+func ForceSetColorLevel(level Level) Level { return std.SetLevel(level) }
+
+// SetSupportLink set the terminal supports OSC 8 hyperlinks, returns old value. see Link()
+func SetSupportLink(support bool) bool { return std.SetSupportLink(support) }
 
 // ForceColor force open color render
 func ForceColor() Level { return ForceOpenColor() }
@@ -154,62 +172,12 @@ func InnerErrs() []error { return innerErrs }
 // Usage:
 //
 //	msg := RenderCode("3;32;45", "some", "message")
-func RenderCode(code string, args ...any) string {
-	var message string
-
-	// Fast path optimizations
-	if ln := len(args); ln == 1 {
-		// Single argument - avoid fmt.Sprint overhead
-		if str, ok := args[0].(string); ok {
-			message = str
-		} else {
-			message = fmt.Sprint(args[0])
-		}
-	} else if ln == 2 {
-		// Two arguments - common case, try to optimize if both are strings
-		if str1, ok1 := args[0].(string); ok1 {
-			if str2, ok2 := args[1].(string); ok2 {
-				message = str1 + str2
-			} else {
-				message = fmt.Sprint(args...)
-			}
-		} else {
-			message = fmt.Sprint(args...)
-		}
-	} else if ln == 0 {
-		return ""
-	} else {
-		// Multiple arguments - use fmt.Sprint for safety
-		message = fmt.Sprint(args...)
-	}
-
-	if len(code) == 0 {
-		return message
-	}
-
-	// disabled OR not support color
-	if !Enable || !SupportColor() {
-		return ClearCode(message)
-	}
-
-	// return fmt.Sprintf(FullColorTpl, code, message)
-	return StartSet + code + "m" + message + ResetSet
-}
+func RenderCode(code string, args ...any) string { return std.RenderCode(code, args...) }
 
 // RenderWithSpaces Render code with spaces.
 // If the number of args is > 1, a space will be added between the args
 func RenderWithSpaces(code string, args ...any) string {
-	msg := formatLikePrintln(args)
-	if len(code) == 0 {
-		return msg
-	}
-
-	// disabled OR not support color
-	if !Enable || !SupportColor() {
-		return ClearCode(msg)
-	}
-
-	return StartSet + code + "m" + msg + ResetSet
+	return std.RenderWithSpaces(code, args...)
 }
 
 // RenderString render a string with color code.
@@ -217,33 +185,12 @@ func RenderWithSpaces(code string, args ...any) string {
 // Usage:
 //
 //	msg := RenderString("3;32;45", "a message")
-func RenderString(code string, str string) string {
-	if len(code) == 0 || str == "" {
-		return str
-	}
-
-	// disabled OR not support color
-	if !Enable || !SupportColor() {
-		return ClearCode(str)
-	}
-
-	open := StartSet + code + "m"
-	// If the string contains reset sequences, re-apply our color after each
-	// reset so that nested colored args don't break the outer color.
-	if strings.Contains(str, ResetSet) {
-		str = strings.ReplaceAll(str, ResetSet, ResetSet+open)
-	}
-	return open + str + ResetSet
-}
+func RenderString(code string, str string) string { return std.RenderString(code, str) }
 
-// ClearCode clear color codes.
+// ClearCode clear color codes and all other ANSI escape sequences. see ansi.Strip()
 //
 // eg:
 //
 //	"\033[36;1mText\x1b[0m" -> "Text"
-func ClearCode(str string) string {
-	if !strings.Contains(str, CodeSuffix) {
-		return str
-	}
-	return codeRegex.ReplaceAllString(str, "")
-}
+//	"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\" -> "link"
+func ClearCode(str string) string { return ansi.Strip(str) }
@@ -10,13 +10,13 @@ import (
 type Color uint8
 type Basic = Color // alias of Color
 
-// Opts basic color options. code: 0 - 9
+// Opts color options. code: 0 - 9, and the extended options 21 - 29, 53, 55
 type Opts []Color
 
-// Add option value
+// Add option value, the non option value will be ignored.
 func (o *Opts) Add(ops ...Color) {
 	for _, op := range ops {
-		if uint8(op) < 10 {
+		if isOptCode(uint8(op)) {
 			*o = append(*o, op)
 		}
 	}
@@ -37,6 +37,19 @@ func (o Opts) String() string {
 	return Colors2code(o...)
 }
 
+// append the options code to dst, will add ";" before each code if dst[start:] is not empty.
+func (o Opts) appendCode(dst []byte, start int) []byte {
+	for _, c := range o {
+		dst = append(appendCodeSep(dst, start), uint8Codes[c]...)
+	}
+	return dst
+}
+
+// check is the option code or the extended option code: 0-9, 21-29, 53, 55
+func isOptCode(val uint8) bool {
+	return val < OptMax || val >= 21 && val <= 29 || val == 53 || val == 55
+}
+
 /*************************************************************
  * Basic 16 color definition
  *************************************************************/
@@ -134,6 +147,56 @@ const (
 	OpStrikethrough              // 9 删除的，删除线(未广泛支持)
 )
 
+// Extended option settings, not supported by all terminals.
+const (
+	OpDoubleUnderline Color = 21 // 21 双下划线(部分终端会作为关闭加粗)
+	OpOverline        Color = 53 // 53 上划线
+)
+
+// UnderlineStyle the underline style, render as the SGR code "4:0" - "4:5". the zero value is not set.
+//
+// On Level16 the styles are downgrade to the plain underline "4", UnderlineNone to "24".
+//
+// Usage:
+//
+//	s := color.HEXStyle("eee").SetUl(color.HEX("f00")).SetUlStyle(color.UnderlineCurly)
+type UnderlineStyle uint8
+
+// Underline styles
+const (
+	UnderlineNone   UnderlineStyle = iota + 1 // 4:0 关闭下划线
+	UnderlineSingle                           // 4:1 单下划线
+	UnderlineDouble                           // 4:2 双下划线
+	UnderlineCurly                            // 4:3 波浪下划线
+	UnderlineDotted                           // 4:4 点状下划线
+	UnderlineDashed                           // 4:5 虚线下划线
+)
+
+// Code convert to code string. eg: UnderlineCurly is "4:3". returns empty if not set.
+func (u UnderlineStyle) Code() string {
+	if !u.IsValid() {
+		return ""
+	}
+	return "4:" + uint8Codes[u-1]
+}
+
+// String convert to code string. see Code()
+func (u UnderlineStyle) String() string { return u.Code() }
+
+// IsValid check the underline style is set: UnderlineNone - UnderlineDashed
+func (u UnderlineStyle) IsValid() bool { return u >= UnderlineNone && u <= UnderlineDashed }
+
+// get the code for the color level. downgrade to "4" or "24" on Level16.
+func (u UnderlineStyle) levelCode(level Level) string {
+	if level == Level16 && u.IsValid() {
+		if u == UnderlineNone {
+			return "24"
+		}
+		return "4"
+	}
+	return u.Code()
+}
+
 // There are basic and light foreground color aliases
 const (
 	Red     = FgRed
@@ -340,10 +403,26 @@ func (c Color) RGB() RGBColor {
 }
 
 // Code convert to code string. eg "35"
-func (c Color) Code() string { return strconv.FormatInt(int64(c), 10) }
+func (c Color) Code() string { return uint8Codes[c] }
 
 // String convert to code string. eg "35"
-func (c Color) String() string { return strconv.FormatInt(int64(c), 10) }
+func (c Color) String() string { return uint8Codes[c] }
+
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+//
+// Usage:
+//
+//	buf = color.FgGreen.AppendRender(buf[:0], "message")
+//
+// It renders by the default renderer, see Renderer.AppendStyle()
+func (c Color) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(append(dst, uint8Codes[c]...), start, str)
+}
 
 // IsBg check is background color
 func (c Color) IsBg() bool {
@@ -417,6 +496,14 @@ var ExBgColors = map[string]Color{
 	"lightWhite":   BgLightWhite,
 }
 
+// cached code strings of the uint8 values. eg: uint8Codes[35] = "35"
+var uint8Codes = func() (codes [256]string) {
+	for i := range codes {
+		codes[i] = strconv.Itoa(i)
+	}
+	return
+}()
+
 // Options color options map
 //
 // Deprecated: please use AllOptions instead.
@@ -432,6 +519,9 @@ var AllOptions = map[string]Color{
 	"blink":      OpBlink,
 	"reverse":    OpReverse,
 	"concealed":  OpConcealed,
+	// extended options
+	"doubleunderline": OpDoubleUnderline,
+	"overline":        OpOverline,
 }
 
 var (
@@ -469,6 +559,9 @@ var (
 		5: "blink",
 		7: "reverse",
 		8: "concealed",
+		// extended options
+		21: "doubleUnderline",
+		53: "overline",
 	}
 )
 
@@ -3,7 +3,6 @@ package color
 import (
 	"fmt"
 	"strconv"
-	"strings"
 )
 
 /*
@@ -28,6 +27,7 @@ from wikipedia, 256 color:
 //	fg "\x1b[38;5;242m"
 //	bg "\x1b[48;5;208m"
 //	both "\x1b[38;5;242;48;5;208m"
+//	underline color "\x1b[4;58;5;196m"
 //
 // links:
 //
@@ -35,14 +35,25 @@ from wikipedia, 256 color:
 const (
 	TplFg256 = "38;5;%d"
 	TplBg256 = "48;5;%d"
+	TplUl256 = "58;5;%d"
 	Fg256Pfx = "38;5;"
 	Bg256Pfx = "48;5;"
+	Ul256Pfx = "58;5;"
 )
 
 /*************************************************************
  * 8bit(256) Color: Bit8Color Color256
  *************************************************************/
 
+// cached code strings of the 256 colors. eg: fg256Codes[12] = "38;5;12"
+var fg256Codes, bg256Codes = func() (fgs, bgs [256]string) {
+	for i := range fgs {
+		fgs[i] = Fg256Pfx + uint8Codes[i]
+		bgs[i] = Bg256Pfx + uint8Codes[i]
+	}
+	return
+}()
+
 // Color256 256 color (8 bit), uint8 range at 0 - 255.
 // Support 256 color on windows CMD, PowerShell
 //
@@ -126,7 +137,7 @@ func (c Color256) RGBColor() RGBColor { return c.RGB() }
 func (c Color256) Value() uint8 { return c[0] }
 
 // Code convert to color code string. eg: "12"
-func (c Color256) Code() string { return strconv.Itoa(int(c[0])) }
+func (c Color256) Code() string { return uint8Codes[c[0]] }
 
 // FullCode convert to color code string with prefix. eg: "38;5;12"
 func (c Color256) FullCode() string { return c.String() }
@@ -134,15 +145,40 @@ func (c Color256) FullCode() string { return c.String() }
 // String convert to color code string with prefix. eg: "38;5;12"
 func (c Color256) String() string {
 	if c[1] == AsFg { // 0 is Fg
-		return Fg256Pfx + strconv.Itoa(int(c[0]))
+		return fg256Codes[c[0]]
 	}
 
 	if c[1] == AsBg { // 1 is Bg
-		return Bg256Pfx + strconv.Itoa(int(c[0]))
+		return bg256Codes[c[0]]
 	}
 	return "" // empty
 }
 
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+func (c Color256) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(c.appendCode(dst, std.level), start, str)
+}
+
+// append the color code for the color level to dst. eg: "38;5;12"
+func (c Color256) appendCode(dst []byte, level Level) []byte {
+	if c.IsEmpty() {
+		return dst
+	}
+
+	if level == Level16 {
+		return strconv.AppendUint(dst, uint64(C256ToBasic(c[0], c[1] == AsBg)), 10)
+	}
+	if c[1] == AsBg {
+		return append(dst, bg256Codes[c[0]]...)
+	}
+	return append(dst, fg256Codes[c[0]]...)
+}
+
 // IsFg color
 func (c Color256) IsFg() bool { return c[1] == AsFg }
 
@@ -180,6 +216,10 @@ type Style256 struct {
 	opts Opts
 	// fg and bg color
 	fg, bg Color256
+	// ul the underline color
+	ul Color256
+	// ulStyle the underline style
+	ulStyle UnderlineStyle
 }
 
 // S256 create a color256 style
@@ -223,6 +263,22 @@ func (s *Style256) SetFg(fgVal uint8) *Style256 {
 	return s
 }
 
+// SetUl set underline color value, should use with an underline option or style.
+//
+// Usage:
+//
+//	s := color.S256(255).SetUl(196).SetUlStyle(color.UnderlineCurly)
+func (s *Style256) SetUl(ulVal uint8) *Style256 {
+	s.ul = Color256{ulVal, 1}
+	return s
+}
+
+// SetUlStyle set underline style. eg: UnderlineCurly
+func (s *Style256) SetUlStyle(ulStyle UnderlineStyle) *Style256 {
+	s.ulStyle = ulStyle
+	return s
+}
+
 // SetOpts set options
 func (s *Style256) SetOpts(opts Opts) *Style256 {
 	s.opts = opts
@@ -263,17 +319,40 @@ func (s *Style256) Code() string { return s.String() }
 
 // String convert to color code string
 func (s *Style256) String() string {
-	var ss []string
+	return string(s.appendCode(make([]byte, 0, 32), LevelRgb))
+}
+
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+func (s *Style256) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(s.appendCode(dst, std.level), start, str)
+}
+
+// append the style code for the color level to dst. eg: "38;5;12;48;5;23;1"
+func (s *Style256) appendCode(dst []byte, level Level) []byte {
+	start := len(dst)
 	if s.fg[1] > 0 {
-		ss = append(ss, Fg256Pfx+strconv.FormatInt(int64(s.fg[0]), 10))
+		dst = Color256{s.fg[0], AsFg}.appendCode(dst, level)
 	}
 
 	if s.bg[1] > 0 {
-		ss = append(ss, Bg256Pfx+strconv.FormatInt(int64(s.bg[0]), 10))
+		dst = Color256{s.bg[0], AsBg}.appendCode(appendCodeSep(dst, start), level)
+	}
+
+	// underline color, not supported on Level16
+	if s.ul[1] > 0 && level != Level16 {
+		dst = append(append(appendCodeSep(dst, start), Ul256Pfx...), uint8Codes[s.ul[0]]...)
 	}
 
 	if s.opts.IsValid() {
-		ss = append(ss, s.opts.String())
+		dst = s.opts.appendCode(dst, start)
+	}
+	if s.ulStyle.IsValid() {
+		dst = append(appendCodeSep(dst, start), s.ulStyle.levelCode(level)...)
 	}
-	return strings.Join(ss, ";")
+	return dst
 }
@@ -3,7 +3,6 @@ package color
 import (
 	"fmt"
 	"strconv"
-	"strings"
 )
 
 // 24 bit RGB color
@@ -26,11 +25,14 @@ import (
 //	fg: \x1b[38;2;30;144;255mMESSAGE\x1b[0m
 //	bg: \x1b[48;2;30;144;255mMESSAGE\x1b[0m
 //	both: \x1b[38;2;233;90;203;48;2;30;144;255mMESSAGE\x1b[0m
+//	underline color: \x1b[4;58;2;255;0;0mMESSAGE\x1b[0m
 const (
 	TplFgRGB = "38;2;%d;%d;%d"
 	TplBgRGB = "48;2;%d;%d;%d"
+	TplUlRGB = "58;2;%d;%d;%d"
 	FgRGBPfx = "38;2;"
 	BgRGBPfx = "48;2;"
+	UlRGBPfx = "58;2;"
 )
 
 // mark color is fg or bg.
@@ -207,7 +209,7 @@ func (c RGBColor) Values() []int {
 }
 
 // Code to color code string without prefix. eg: "204;123;56"
-func (c RGBColor) Code() string { return fmt.Sprintf("%d;%d;%d", c[0], c[1], c[2]) }
+func (c RGBColor) Code() string { return string(c.appendValues(make([]byte, 0, 12))) }
 
 // Hex color rgb to hex string. as in "ff0080".
 func (c RGBColor) Hex() string { return fmt.Sprintf("%02x%02x%02x", c[0], c[1], c[2]) }
@@ -222,16 +224,48 @@ func (c RGBColor) FullCode() string { return c.String() }
 
 // String to color code string with prefix. eg: "38;2;204;123;56"
 func (c RGBColor) String() string {
-	if c[3] == AsFg {
-		return fmt.Sprintf(TplFgRGB, c[0], c[1], c[2])
+	// c[3] > 1 is empty
+	if c.IsEmpty() {
+		return ""
 	}
+	return string(c.appendCode(make([]byte, 0, 20), LevelRgb))
+}
 
-	if c[3] == AsBg {
-		return fmt.Sprintf(TplBgRGB, c[0], c[1], c[2])
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+func (c RGBColor) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
 	}
+	return appendRenderEnd(c.appendCode(dst, std.level), start, str)
+}
 
-	// c[3] > 1 is empty
-	return ""
+// append the color code for the color level to dst. eg: "38;2;204;123;56"
+func (c RGBColor) appendCode(dst []byte, level Level) []byte {
+	if c.IsEmpty() {
+		return dst
+	}
+
+	isBg := c[3] == AsBg
+	switch level {
+	case Level16:
+		return strconv.AppendUint(dst, uint64(rgbToBasic(c[0], c[1], c[2], isBg)), 10)
+	case Level256:
+		return Color256{RgbTo256(c[0], c[1], c[2]), c[3]}.appendCode(dst, level)
+	}
+
+	if isBg {
+		return c.appendValues(append(dst, BgRGBPfx...))
+	}
+	return c.appendValues(append(dst, FgRGBPfx...))
+}
+
+// append the rgb values to dst. eg: "204;123;56"
+func (c RGBColor) appendValues(dst []byte) []byte {
+	dst = append(strconv.AppendUint(dst, uint64(c[0]), 10), ';')
+	dst = append(strconv.AppendUint(dst, uint64(c[1]), 10), ';')
+	return strconv.AppendUint(dst, uint64(c[2]), 10)
 }
 
 // ToBg convert to background color
@@ -289,6 +323,10 @@ type RGBStyle struct {
 	opts Opts
 	// fg and bg color
 	fg, bg RGBColor
+	// ul the underline color
+	ul RGBColor
+	// ulStyle the underline style
+	ulStyle UnderlineStyle
 }
 
 // NewRGBStyle create a RGBStyle.
@@ -353,6 +391,23 @@ func (s *RGBStyle) SetBg(bg RGBColor) *RGBStyle {
 	return s
 }
 
+// SetUl set underline color, should use with an underline option or style.
+//
+// Usage:
+//
+//	s := color.HEXStyle("eee").SetUl(color.HEX("f00")).SetUlStyle(color.UnderlineCurly)
+func (s *RGBStyle) SetUl(ul RGBColor) *RGBStyle {
+	ul[3] = 1 // add fixed value, mark is valid
+	s.ul = ul
+	return s
+}
+
+// SetUlStyle set underline style. eg: UnderlineCurly
+func (s *RGBStyle) SetUlStyle(ulStyle UnderlineStyle) *RGBStyle {
+	s.ulStyle = ulStyle
+	return s
+}
+
 // SetOpts set color options
 func (s *RGBStyle) SetOpts(opts Opts) *RGBStyle {
 	s.opts = opts
@@ -396,22 +451,49 @@ func (s *RGBStyle) FullCode() string { return s.String() }
 
 // String convert to color code string
 func (s *RGBStyle) String() string {
-	var ss []string
+	return string(s.appendCode(make([]byte, 0, 48), LevelRgb))
+}
+
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+func (s *RGBStyle) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(s.appendCode(dst, std.level), start, str)
+}
+
+// append the style code for the color level to dst. eg: "38;2;204;123;56;48;2;0;0;0;1"
+func (s *RGBStyle) appendCode(dst []byte, level Level) []byte {
+	start := len(dst)
 	// last value ensure is enable.
 	if s.fg[3] == 1 {
-		ss = append(ss, fmt.Sprintf(TplFgRGB, s.fg[0], s.fg[1], s.fg[2]))
+		dst = RGBColor{s.fg[0], s.fg[1], s.fg[2], AsFg}.appendCode(dst, level)
 	}
 
 	if s.bg[3] == 1 {
-		ss = append(ss, fmt.Sprintf(TplBgRGB, s.bg[0], s.bg[1], s.bg[2]))
+		dst = RGBColor{s.bg[0], s.bg[1], s.bg[2], AsBg}.appendCode(appendCodeSep(dst, start), level)
 	}
 
-	if s.opts.IsValid() {
-		ss = append(ss, s.opts.String())
+	// underline color, not supported on Level16
+	if s.ul[3] == 1 && level != Level16 {
+		dst = appendCodeSep(dst, start)
+		if level == Level256 {
+			dst = append(append(dst, Ul256Pfx...), uint8Codes[RgbTo256(s.ul[0], s.ul[1], s.ul[2])]...)
+		} else {
+			dst = s.ul.appendValues(append(dst, UlRGBPfx...))
+		}
 	}
 
-	return strings.Join(ss, ";")
+	if s.opts.IsValid() {
+		dst = s.opts.appendCode(dst, start)
+	}
+	if s.ulStyle.IsValid() {
+		dst = append(appendCodeSep(dst, start), s.ulStyle.levelCode(level)...)
+	}
+	return dst
 }
 
 // IsEmpty style
-func (s *RGBStyle) IsEmpty() bool { return s.fg[3] != 1 && s.bg[3] != 1 }
+func (s *RGBStyle) IsEmpty() bool { return s.fg[3] != 1 && s.bg[3] != 1 && s.ul[3] != 1 }
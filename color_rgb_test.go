@@ -66,6 +66,32 @@ func TestRGBColor(t *testing.T) {
 	is.Equal("\x1b[38;2;204;204;204mmsg\x1b[0m\n", str)
 }
 
+func TestRGBColor_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	c := RGB(204, 123, 56)
+	is.Eq("38;2;204;123;56", c.String())
+	is.Eq("204;123;56", c.Code())
+	is.Eq("48;2;204;123;56", c.ToBg().String())
+	is.Eq("", RGBColor{0, 0, 0, 2}.String())
+	is.Eq(c.Sprint("msg"), string(c.AppendRender(nil, "msg")))
+
+	// downgrade to current level
+	ForceSetColorLevel(Level256)
+	is.Eq("\x1b[38;5;173mmsg\x1b[0m", string(c.AppendRender(nil, "msg")))
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[31mmsg\x1b[0m", string(RGB(197, 30, 20).AppendRender(nil, "msg")))
+	ForceSetColorLevel(LevelRgb)
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = c.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+}
+
 func TestRGBColor_set_reset(t *testing.T) {
 	_, err := Reset()
 	assert.NoError(t, err)
@@ -215,6 +241,23 @@ func TestRGBStyle(t *testing.T) {
 	is.Equal("\x1b[38;2;20;144;234;48;2;234;78;23mmsg\x1b[0m\n", str)
 }
 
+func TestRGBStyle_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := NewRGBStyle(RGB(20, 144, 234), RGB(234, 78, 23)).AddOpts(OpBold)
+	is.Eq("38;2;20;144;234;48;2;234;78;23;1", s.String())
+	is.Eq(s.Sprint("msg"), string(s.AppendRender(nil, "msg")))
+	is.Eq("48;2;234;78;23", (&RGBStyle{}).SetBg(RGB(234, 78, 23)).String())
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = s.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+}
+
 func TestPrintRGBColor(t *testing.T) {
 	RGB(30, 144, 255).Println("message. use RGB number")
 	HEX("#1976D2").Println("blue-darken")
@@ -231,6 +274,25 @@ func TestRGBStyle_SetOpts(t *testing.T) {
 	s.Println("RGB style message with options")
 }
 
+func TestRGBStyle_SetUl(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := HEXStyle("eee").SetUl(HEX("f00")).SetUlStyle(UnderlineDouble)
+	is.False(s.IsEmpty())
+	is.Eq("38;2;238;238;238;58;2;255;0;0;4:2", s.String())
+	is.Eq("\x1b[38;2;238;238;238;58;2;255;0;0;4:2mMSG\x1b[0m", s.Sprint("MSG"))
+	is.False(new(RGBStyle).SetUl(RGB(1, 2, 3)).IsEmpty())
+
+	ForceSetColorLevel(Level256)
+	is.Eq("\x1b[38;5;15;58;5;9;4:2mMSG\x1b[0m", s.Sprint("MSG"))
+	is.Eq("\x1b[38;5;15;58;5;9;4:2mMSG\x1b[0m", string(s.AppendRender(nil, "MSG")))
+
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[37;4mMSG\x1b[0m", string(s.AppendRender(nil, "MSG")))
+}
+
 func testRgbToC256Color(t *testing.T, name string, c RGBColor, expected uint8) {
 	t.Log("RGB Color:", c.Sprint(name))
 	t.Log("256 Color:", c.C256().Sprint(name))
@@ -3,6 +3,7 @@ package color
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -10,6 +11,8 @@ import (
 const (
 	// MatchExpr regex to match color tags
 	//
+	// Deprecated: TagParser.Parse is not use it, it uses the tag lexer for support nested tags.
+	//
 	// Notice: golang 不支持反向引用. 即不支持使用 \1 引用第一个匹配 ([a-z=;]+)
 	// MatchExpr = `<([a-z=;]+)>(.*?)<\/\1>`
 	// 所以调整一下 统一使用 `</>` 来结束标签，例如 "<info>some text</>"
@@ -20,20 +23,19 @@ const (
 
 	// AttrExpr regex to match custom color attributes
 	// eg: "<fg=white;bg=blue;op=bold>content</>"
+	//
+	// Deprecated: ParseCodeFromAttr is not use it, it uses the tag lexer.
 	AttrExpr = `(fg|bg|op)[\s]*=[\s]*([0-9a-zA-Z,]+);?`
 
 	// StripExpr regex used for removing color tags
+	//
+	// Deprecated: ClearTag is not use it, it uses the tag lexer.
+	//
 	// StripExpr = `<[\/]?[a-zA-Z=;]+>`
 	// 随着上面的做一些调整
 	StripExpr = `<[\/]?[0-9a-zA-Z_=,;]*>`
 )
 
-var (
-	attrRegex  = regexp.MustCompile(AttrExpr)
-	matchRegex = regexp.MustCompile(MatchExpr)
-	stripRegex = regexp.MustCompile(StripExpr)
-)
-
 /*************************************************************
  * internal defined color tags
  *************************************************************/
@@ -227,7 +229,7 @@ var (
 		"cyan":    "46",
 		"magenta": "45",
 		"mga":     "45",
-		"white":   FgWhite.Code(),
+		"white":   "47",
 		"default": "49", // no color
 		"normal":  "49", // no color
 
@@ -282,6 +284,46 @@ var (
 		"concealed":     OpConcealed.Code(),
 		"strikethrough": OpStrikethrough.Code(),
 		"st":            OpStrikethrough.Code(),
+
+		// alias names and more SGR attributes
+
+		"dim":             "2", // same "fuzzy"
+		"faint":           "2",
+		"underline":       "4",
+		"inverse":         "7", // same "reverse"
+		"hidden":          "8", // same "concealed"
+		"conceal":         "8",
+		"strike":          "9",
+		"fraktur":         "20",
+		"doubleunderline": "21",
+		"dul":             "21",
+		"normal":          "22", // not bold and not faint
+		"nobold":          "22",
+		"noitalic":        "23",
+		"nounderline":     "24",
+		"noblink":         "25",
+		"noreverse":       "27",
+		"reveal":          "28", // not concealed
+		"nostrike":        "29",
+		"framed":          "51",
+		"encircled":       "52",
+		"overline":        "53",
+		"noframed":        "54", // not framed and not encircled
+		"nooverline":      "55",
+		"superscript":     "73",
+		"subscript":       "74",
+	}
+
+	// underline styles for the "ul" attribute, use the SGR sub-parameter "4:n".
+	attrUlStyles = map[string]string{
+		"none":     "4:0",
+		"single":   "4:1",
+		"straight": "4:1",
+		"double":   "4:2",
+		"curly":    "4:3",
+		"wavy":     "4:3",
+		"dotted":   "4:4",
+		"dashed":   "4:5",
 	}
 )
 
@@ -291,14 +333,26 @@ var (
 
 var (
 	tagParser = TagParser{}
-	// regex for match color 256 code
-	rxNumStr  = regexp.MustCompile("^[0-9]{1,3}$")
+	// regex for match hex color code
 	rxHexCode = regexp.MustCompile("^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$")
 )
 
+// TagFunc render function for a custom tag.
+//
+// body is the plain text of the tag content, the inner tags are removed and not rendered.
+// The returned text is rendered by the enclosing style.
+// attrs is the tag attributes, eg: "<pad=10>" -> {"pad": "10"}. it's empty for "<upper>".
+type TagFunc func(body string, attrs map[string]string) string
+
 // TagParser struct
 type TagParser struct {
 	disable bool
+	// rd the renderer for render color code. default is the std renderer.
+	rd *Renderer
+	// tags custom registered color tags. see RegisterTag()
+	tags map[string]string
+	// funcs custom registered tag render functions. see RegisterTagFunc()
+	funcs map[string]TagFunc
 }
 
 // NewTagParser create
@@ -311,20 +365,101 @@ func NewTagParser() *TagParser {
 // 	return tp
 // }
 
-// ParseByEnv parse given string. will check package setting.
+// RegisterTag register a custom color tag to the parser, it will override the built-in tag.
+//
+// Usage:
+//
+//	p.RegisterTag("title", "1;4;36")
+//	p.Parse("<title>Title</>")
+func (tp *TagParser) RegisterTag(name, code string) {
+	if tp.tags == nil {
+		tp.tags = make(map[string]string)
+	}
+	tp.tags[name] = code
+}
+
+// RegisterTagFunc register a custom tag with render function to the parser.
+// The function can transform the tag body, it will override the color tag with same name.
+//
+// Usage:
+//
+//	p.RegisterTagFunc("upper", func(body string, _ map[string]string) string {
+//		return strings.ToUpper(body)
+//	})
+//	p.Parse("<upper>hello</>") // "HELLO"
+//
+//	// tag with attributes. eg: "<pad=10>"
+//	p.RegisterTagFunc("pad", func(body string, attrs map[string]string) string {
+//		n, _ := strconv.Atoi(attrs["pad"])
+//		return fmt.Sprintf("%-*s", n, body)
+//	})
+func (tp *TagParser) RegisterTagFunc(name string, fn TagFunc) {
+	if tp.funcs == nil {
+		tp.funcs = make(map[string]TagFunc)
+	}
+	tp.funcs[name] = fn
+}
+
+// get the renderer of the parser
+func (tp *TagParser) renderer() *Renderer {
+	if tp.rd != nil {
+		return tp.rd
+	}
+	return std
+}
+
+// ParseByEnv parse given string. will check the renderer setting.
 func (tp *TagParser) ParseByEnv(str string) string {
+	rd := tp.renderer()
 	// disable handler TAG
-	if !RenderTag {
+	if !*rd.renderTag {
 		return str
 	}
 
 	// disable OR not support color
-	if !Enable || !SupportColor() {
-		return ClearTag(str)
+	if !rd.canRender() {
+		return tp.clearTags(str)
 	}
 	return tp.Parse(str)
 }
 
+// clear color tags in the string. will call the tag functions if has registered,
+// the link tags are output as "text (url)".
+func (tp *TagParser) clearTags(str string) string {
+	if len(tp.funcs) == 0 && !strings.Contains(str, "<link=") {
+		return tp.stripTags(str)
+	}
+	return tp.parse(str, false)
+}
+
+// remove the color tags in the string, only the paired tags are removed like parse.
+func (tp *TagParser) stripTags(str string) string {
+	hasEscape := strings.Contains(str, `\<`)
+	if !strings.Contains(str, "</") && !hasEscape {
+		return str
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(str))
+
+	var last int
+	for _, tok := range tp.pairTags(str) {
+		text := str[last:tok.start]
+		if hasEscape {
+			text = unescapeTag(text, true)
+		}
+		sb.WriteString(text)
+		last = tok.end
+	}
+
+	text := str[last:]
+	if hasEscape {
+		text = unescapeTag(text, false)
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
+
 // Parse given string, replace color tag and return rendered string
 //
 // Use built in tags:
@@ -336,52 +471,253 @@ func (tp *TagParser) ParseByEnv(str string) string {
 //
 //	`<fg=VALUE;bg=VALUE;op=VALUES>CONTENT</>`
 //	// e.g: `<fg=167;bg=232>wel</>`
+//
+// Tags can be nested, the enclosing style is restored after each inner `</>`:
+//
+//	`<info>a <red>b</> c</>` // "c" is rendered with the info style
+//
+// Close tag can use the tag name, it will auto close the inner tags:
+//
+//	`<info>a <fg=red>b</fg=red> c</info>`
+//	`<info>a <red>b c</info>` // "<red>" is closed by "</info>"
+//
+// Unknown tags, unmatched close tags and unclosed tags are kept as literal text.
+//
+// Use `\<` to output a literal `<`, it will not be parsed as a tag. see EscapeTag()
+//
+//	`<info>List\<T></>` // output "List<T>" with info style
 func (tp *TagParser) Parse(str string) string {
+	return tp.parse(str, true)
+}
+
+// tagFrame a render frame of the tag function or the root.
+type tagFrame struct {
+	sb strings.Builder
+	// fn the tag function, nil for the root frame
+	fn   TagFunc
+	name string
+	// url of the link tag frame
+	url string
+	// plain the text is not rendered. it's the tag function frame or in it.
+	plain bool
+	// codes style stack in the frame, item is the color code of the open tag
+	codes []string
+}
+
+// write text to the frame, render it by the style stack if render is true.
+func (f *tagFrame) write(rd *Renderer, text string, render bool) {
+	if render && !f.plain && len(f.codes) > 0 {
+		f.sb.WriteString(rd.RenderString(strings.Join(f.codes, ";"), text))
+	} else {
+		f.sb.WriteString(text)
+	}
+}
+
+// parse the string. render: render the color code, otherwise only remove color tags.
+func (tp *TagParser) parse(str string, render bool) string {
+	hasEscape := strings.Contains(str, `\<`)
 	// not contains color tag
-	if !strings.Contains(str, "</>") {
+	if !strings.Contains(str, "</") && !hasEscape {
 		return str
 	}
 
-	// find color tags by regex. str eg: "<fg=white;bg=blue;op=bold>content</>"
-	matched := matchRegex.FindAllStringSubmatch(str, -1)
+	toks := tp.pairTags(str)
+	if len(toks) == 0 {
+		if hasEscape {
+			return unescapeTag(str, false)
+		}
+		return str
+	}
+
+	rd := tp.renderer()
+	frames := []*tagFrame{{}}
+	frames[0].sb.Grow(len(str) + len(toks)*8)
+
+	// stack of the open tags
+	var opens []tagToken
+	var last int
+	for _, tok := range toks {
+		text := str[last:tok.start]
+		if hasEscape {
+			text = unescapeTag(text, true)
+		}
 
-	// item: 0 full text 1 tag name 2 tag content
-	for _, item := range matched {
-		full, tag, body := repairMatchedTag(item[0], item[1], item[2])
+		top := frames[len(frames)-1]
+		top.write(rd, text, render)
+		last = tok.end
 
-		// use defined color tag name: "<info>content</>" -> tag: "info"
-		if !strings.ContainsRune(tag, '=') {
-			if code := colorTags[tag]; len(code) > 0 {
-				str = strings.Replace(str, full, RenderString(code, body), 1)
-			} else if code, ok := namedRgbMap[tag]; ok {
-				code = strings.Replace(code, ",", ";", -1)
-				now := RenderString(FgRGBPfx+code, body)
-				str = strings.Replace(str, full, now, 1)
+		if tok.kind == tagOpen {
+			opens = append(opens, tok)
+			if tok.fn != nil || tok.url != "" {
+				plain := top.plain || tok.fn != nil
+				frames = append(frames, &tagFrame{fn: tok.fn, name: tok.name, url: tok.url, plain: plain})
+			} else {
+				top.codes = append(top.codes, tok.code)
 			}
 			continue
 		}
 
-		// custom color in tag
-		// - basic: "fg=white;bg=blue;op=bold"
-		if code := ParseCodeFromAttr(tag); len(code) > 0 {
-			str = strings.Replace(str, full, RenderString(code, body), 1)
+		// close tag, maybe close multi inner tags
+		for i := 0; i < tok.closes; i++ {
+			open := opens[len(opens)-1]
+			opens = opens[:len(opens)-1]
+
+			top = frames[len(frames)-1]
+			if open.fn == nil && open.url == "" {
+				top.codes = top.codes[:len(top.codes)-1]
+				continue
+			}
+
+			// call the tag function or render the link, write result to the parent frame
+			frames = frames[:len(frames)-1]
+			var out string
+			if top.fn != nil {
+				out = top.fn(top.sb.String(), parseTagAttrs(top.name))
+			} else {
+				out = rd.renderLink(top.url, top.sb.String(), render && !top.plain)
+			}
+			frames[len(frames)-1].write(rd, out, render)
 		}
 	}
 
-	return str
+	text := str[last:]
+	if hasEscape {
+		text = unescapeTag(text, false)
+	}
+	frames[0].sb.WriteString(text)
+	return frames[0].sb.String()
+}
+
+// parse the tag attributes. eg: "pad=10" -> {"pad": "10"}
+func parseTagAttrs(name string) map[string]string {
+	attrs := make(map[string]string)
+	if strings.IndexByte(name, '=') > 0 {
+		lexAttrs(name, func(key, val string, _ int) {
+			attrs[key] = val
+		})
+	}
+	return attrs
+}
+
+// pairTags find color tags in the string, pair the open and close tags by a stack.
+// only returns the paired tags, others will be kept as literal text.
+func (tp *TagParser) pairTags(str string) []tagToken {
+	var toks []tagToken
+	// index of the open tags in toks
+	var stack []int
+
+	lx := newTagLexer(str)
+	for tok, ok := lx.next(); ok; tok, ok = lx.next() {
+		switch tok.kind {
+		case tagText:
+			continue
+		case tagClose:
+			// find the open tag by name. "</>" will close the last open tag
+			k := len(stack) - 1
+			if tok.name != "" {
+				for k >= 0 && toks[stack[k]].name != tok.name {
+					k--
+				}
+			}
+			if k < 0 {
+				continue // unmatched close tag
+			}
+
+			// auto close the inner tags. eg: "<info>a <red>b</info>"
+			for _, idx := range stack[k:] {
+				toks[idx].paired = true
+			}
+			tok.paired, tok.closes = true, len(stack)-k
+			stack = stack[:k]
+		default: // open tag
+			if tok.code, tok.fn = tp.resolveTag(tok.name); tok.code == "" && tok.fn == nil {
+				if tok.url = linkTagURL(tok.name); tok.url == "" {
+					continue // unknown tag
+				}
+			}
+			stack = append(stack, len(toks))
+		}
+		toks = append(toks, tok)
+	}
+
+	// remove unclosed open tags
+	if len(stack) > 0 {
+		paired := toks[:0]
+		for _, tok := range toks {
+			if tok.paired {
+				paired = append(paired, tok)
+			}
+		}
+		toks = paired
+	}
+
+	// the tag nested in a color tag builds on the enclosing style. eg: "<bold><info>" -> "1;32"
+	var colored []bool
+	for i, tok := range toks {
+		if tok.kind == tagClose {
+			colored = colored[:len(colored)-tok.closes]
+			continue
+		}
+
+		if tok.code != "" && len(colored) > 0 && colored[len(colored)-1] {
+			toks[i].code = tp.tagCode(tok.name, true)
+		}
+		colored = append(colored, tok.code != "")
+	}
+	return toks
 }
 
-func repairMatchedTag(full, tag, body string) (string, string, string) {
-	if len(colorTags[tag]) == 0 && len(namedRgbMap[tag]) == 0 && len(ParseCodeFromAttr(tag)) == 0 {
-		if matched := matchRegex.FindAllStringSubmatch(strings.TrimPrefix(full, "<"+tag+">"), -1); len(matched) > 0 {
-			full = matched[0][0]
-			tag = matched[0][1]
-			body = matched[0][2]
-			return repairMatchedTag(full, tag, body)
+// resolve the tag to color code or a tag function. returns empty on tag is unknown.
+func (tp *TagParser) resolveTag(tag string) (string, TagFunc) {
+	if len(tp.funcs) > 0 {
+		// name of the tag with attributes. eg: "pad=10" -> "pad"
+		name := tag
+		if i := strings.IndexByte(tag, '='); i > 0 {
+			name = tag[:i]
+		}
+
+		if fn, ok := tp.funcs[name]; ok {
+			return "", fn
 		}
 	}
+	return tp.tagCode(tag, false), nil
+}
 
-	return full, tag, body
+// get color code by tag name or attributes. returns empty on tag is unknown.
+//
+// nested: the tag is in a color tag, the leading reset "0;" of the code is not returned,
+// so the inner tag builds on the enclosing style. eg: "<bold><info>" -> "1;32"
+func (tp *TagParser) tagCode(tag string, nested bool) string {
+	code := tp.lookupTagCode(tag)
+	if nested && strings.HasPrefix(code, "0;") {
+		return code[2:]
+	}
+	return code
+}
+
+// find color code by tag name or attributes.
+func (tp *TagParser) lookupTagCode(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	if code, ok := tp.tags[tag]; ok {
+		return code
+	}
+
+	// custom color in tag
+	// - basic: "fg=white;bg=blue;op=bold"
+	if strings.ContainsRune(tag, '=') {
+		return ParseCodeFromAttr(tag)
+	}
+
+	// use defined color tag name: "<info>content</>" -> tag: "info"
+	if code := colorTags[tag]; len(code) > 0 {
+		return code
+	}
+	if code, ok := namedRgbMap[tag]; ok {
+		return FgRGBPfx + strings.Replace(code, ",", ";", -1)
+	}
+	return ""
 }
 
 // ReplaceTag parse string, replace color tag and return rendered string
@@ -394,7 +730,7 @@ func ReplaceTag(str string) string {
 // attr format:
 //
 //	// VALUE please see var: FgColors, BgColors, AllOptions
-//	"fg=VALUE;bg=VALUE;op=VALUE"
+//	"fg=VALUE;bg=VALUE;op=VALUE;ul=VALUE;ulc=VALUE"
 //
 // 16 color:
 //
@@ -418,6 +754,16 @@ func ReplaceTag(str string) string {
 //	// r,g,b
 //	"fg=23,45,214"
 //	"fg=23,45,214;bg=109,99,88"
+//	"fg=rgb(23,45,214)"
+//	// hsl, h: 0-360, s,l: 0-100
+//	"bg=hsl(200,50,40)"
+//	// CSS color name
+//	"fg=aliceblue"
+//
+// Underline style and color:
+//
+//	"ul=curly" // none, single, double, curly, dotted, dashed
+//	"ul=curly;ulc=#ff0000"
 func ParseCodeFromAttr(attr string) (code string) {
 	if !strings.ContainsRune(attr, '=') {
 		return
@@ -429,66 +775,237 @@ func ParseCodeFromAttr(attr string) (code string) {
 	}
 
 	var codes []string
-	matched := attrRegex.FindAllStringSubmatch(attr, -1)
-
-	for _, item := range matched {
-		pos, val := item[1], item[2]
-		switch pos {
-		case "fg":
-			if code, ok := attrFgs[val]; ok { // attr fg
-				codes = append(codes, code)
-			} else if code := rgbHex256toCode(val, false); code != "" {
+	lexAttrs(attr, func(key, val string, _ int) {
+		switch key {
+		case "fg", "bg", "ulc":
+			if code, err := attrColorCode(key, val); err == nil {
 				codes = append(codes, code)
 			}
-		case "bg":
-			if code, ok := attrBgs[val]; ok { // attr bg
-				codes = append(codes, code)
-			} else if code := rgbHex256toCode(val, true); code != "" {
+		case "ul":
+			if code, ok := attrUlStyles[val]; ok {
 				codes = append(codes, code)
 			}
 		case "op": // options allow multi value
-			if strings.Contains(val, ",") {
-				ns := strings.Split(val, ",")
-				for _, n := range ns {
-					if code, ok := attrOpts[n]; ok { // attr ops
-						codes = append(codes, code)
-					}
+			for _, n := range strings.Split(val, ",") {
+				if code, ok := attrOpts[n]; ok { // attr ops
+					codes = append(codes, code)
 				}
-			} else if code, ok := attrOpts[val]; ok {
-				codes = append(codes, code)
 			}
 		}
-	}
+	})
 
 	return strings.Join(codes, ";")
 }
 
-func rgbHex256toCode(val string, isBg bool) (code string) {
-	if len(val) == 6 && rxHexCode.MatchString(val) { // hex: "fc1cac"
-		code = HEX(val, isBg).String()
-	} else if strings.ContainsRune(val, ',') { // rgb: "231,178,161"
-		code = strings.Replace(val, ",", ";", -1)
-		if isBg {
-			code = BgRGBPfx + code
-		} else {
-			code = FgRGBPfx + code
+// attrColorCode convert the color value of the fg, bg, ulc attribute to color code.
+//
+// value formats:
+//
+//	"red"             // 16 color name
+//	"aliceblue"       // CSS color name
+//	"167"             // 256 color
+//	"fc1cac" "#fc1cac" "#f0c"     // hex
+//	"23,45,214" "rgb(23,45,214)" // rgb
+//	"hsl(200,50,40)"  // hsl, h: 0-360, s,l: 0-100
+func attrColorCode(key, val string) (string, error) {
+	// 16 color names
+	switch key {
+	case "fg":
+		if code, ok := attrFgs[val]; ok {
+			return code, nil
+		}
+	case "bg":
+		if code, ok := attrBgs[val]; ok {
+			return code, nil
 		}
-	} else if len(val) < 4 && rxNumStr.MatchString(val) { // 256 code
-		if isBg {
-			code = Bg256Pfx + val
+	case "ulc":
+		if code, ok := attrFgs[val]; ok {
+			return basicToUlColor(code), nil
+		}
+	}
+
+	code, err := parseAttrColor(val)
+	if err != nil {
+		return "", err
+	}
+
+	switch key {
+	case "bg":
+		return "48;" + code, nil
+	case "ulc":
+		return "58;" + code, nil
+	}
+	return "38;" + code, nil
+}
+
+// basicToUlColor convert the basic fg color code to underline color code. eg: "31" -> "58;5;1"
+func basicToUlColor(code string) string {
+	n, _ := strconv.Atoi(code)
+	if n == 39 { // default underline color
+		return "59"
+	}
+
+	if n >= 90 {
+		n -= 82 // 90 - 97 -> 8 - 15
+	} else {
+		n -= 30
+	}
+	return Ul256Pfx + strconv.Itoa(n)
+}
+
+// parseAttrColor parse the 256 or true color value, returns "5;n" or "2;r;g;b"
+func parseAttrColor(val string) (string, error) {
+	if val == "" {
+		return "", ErrInvalidColor
+	}
+
+	if rgb, ok := namedRgbMap[strings.ToLower(val)]; ok {
+		return "2;" + strings.Replace(rgb, ",", ";", -1), nil
+	}
+
+	switch {
+	case strings.HasPrefix(val, "rgb("):
+		if !strings.HasSuffix(val, ")") {
+			return "", ErrInvalidRGB
+		}
+		return parseAttrRGB(val[4 : len(val)-1])
+	case strings.HasPrefix(val, "hsl("):
+		ns, ok := splitAttrInts(strings.TrimSuffix(val[4:], ")"))
+		if !ok || !strings.HasSuffix(val, ")") || ns[0] > 360 || ns[1] > 100 || ns[2] > 100 {
+			return "", ErrInvalidHSL
+		}
+
+		rgb := HslIntToRgb(ns[0], ns[1], ns[2])
+		return fmt.Sprintf("2;%d;%d;%d", rgb[0], rgb[1], rgb[2]), nil
+	case strings.ContainsRune(val, ','): // rgb: "231,178,161"
+		return parseAttrRGB(val)
+	case rxHexCode.MatchString(val) && (val[0] == '#' || len(val) == 6): // hex: "fc1cac" "#fc1cac" "#f0c"
+		rgb := HexToRgb(val)
+		return fmt.Sprintf("2;%d;%d;%d", rgb[0], rgb[1], rgb[2]), nil
+	case isDigits(val): // 256 code: "167"
+		n, err := strconv.Atoi(val)
+		if err != nil || !isValidUint8(n) {
+			return "", Err256OutOfRange
+		}
+		return "5;" + strconv.Itoa(n), nil
+	}
+
+	// starts with "#" or only hex digits. eg: "#ggg", "fc1ca"
+	if val[0] == '#' || isHexDigits(val) {
+		return "", ErrInvalidHex
+	}
+	return "", ErrInvalidColor
+}
+
+// parseAttrRGB parse rgb value "r,g,b" to "2;r;g;b"
+func parseAttrRGB(val string) (string, error) {
+	ns, ok := splitAttrInts(val)
+	if !ok || !isValidUint8(ns[0]) || !isValidUint8(ns[1]) || !isValidUint8(ns[2]) {
+		return "", ErrInvalidRGB
+	}
+	return fmt.Sprintf("2;%d;%d;%d", ns[0], ns[1], ns[2]), nil
+}
+
+// splitAttrInts split the value "n,n,n" to 3 ints
+func splitAttrInts(val string) (ns [3]int, ok bool) {
+	ss := strings.Split(val, ",")
+	if len(ss) != 3 {
+		return
+	}
+
+	for i, s := range ss {
+		s = strings.TrimSpace(s)
+		if !isDigits(s) {
+			return
+		}
+		ns[i], _ = strconv.Atoi(s)
+	}
+	return ns, true
+}
+
+// ClearTag clear all color tags for a string. the escaped `\<` will be unescaped.
+//
+// Same as Parse, the unknown and unclosed tags are kept as literal text. eg: "List<T>"
+func ClearTag(s string) string { return tagParser.stripTags(s) }
+
+// EscapeTag escape the `<` in the string by `\<`, it will be kept as literal text on parse color tags.
+// Use it to safely embed untrusted text in tagged templates.
+//
+// The backslashes before `<` and at the end of the string are doubled, other backslashes are kept as is.
+// So the escaped text keeps its value when a tag follows it. eg: "<info>"+EscapeTag(s)+"</>"
+//
+// Usage:
+//
+//	color.Printf("<info>%s</>", color.EscapeTag("List<T>"))
+func EscapeTag(s string) string {
+	if strings.IndexByte(s, '<') < 0 && !strings.HasSuffix(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+
+	// n the number of backslashes before current char
+	var n int
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '<' {
+			sb.WriteString(strings.Repeat(`\`, n+1))
+		}
+
+		if c == '\\' {
+			n++
 		} else {
-			code = Fg256Pfx + val
+			n = 0
 		}
+		sb.WriteByte(c)
+	}
+
+	// the trailing backslashes, they will be halved when a tag follows
+	sb.WriteString(strings.Repeat(`\`, n))
+	return sb.String()
+}
+
+// isEscapedAt check the '<' at index i is escaped by an odd number of backslashes.
+func isEscapedAt(s string, i int) bool {
+	var n int
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
 	}
-	return
+	return n%2 == 1
 }
 
-// ClearTag clear all tag for a string
-func ClearTag(s string) string {
-	if !strings.Contains(s, "</>") {
+// unescapeTag unescape the `\<` in the text. the backslashes before `<` will be halved.
+// beforeTag: the text is followed by a tag, the backslashes at the end will be halved.
+func unescapeTag(s string, beforeTag bool) string {
+	if strings.IndexByte(s, '\\') < 0 {
 		return s
 	}
-	return stripRegex.ReplaceAllString(s, "")
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(s) && s[j] == '\\' {
+			j++
+		}
+
+		// n the number of backslashes
+		n := j - i
+		if (j < len(s) && s[j] == '<') || (j == len(s) && beforeTag) {
+			n /= 2
+		}
+
+		sb.WriteString(s[i : i+n])
+		i = j
+	}
+	return sb.String()
 }
 
 /*************************************************************
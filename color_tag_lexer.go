@@ -0,0 +1,171 @@
+package color
+
+import "strings"
+
+// tagTokenKind the kind of tag token
+type tagTokenKind uint8
+
+// token kinds of the tag lexer
+const (
+	tagText  tagTokenKind = iota // plain text
+	tagOpen                      // open tag. eg: "<info>", "<fg=red;op=bold>"
+	tagClose                     // close tag. eg: "</>", "</info>"
+)
+
+// tagToken a token in the color tag string.
+type tagToken struct {
+	kind tagTokenKind
+	// start, end byte offsets of the token in the string. tags include the `<` and `>`
+	start, end int
+	// name the tag name or attributes, without `<`, `/` and `>`. empty for text
+	name string
+	// code the color code of the open tag, resolved by the parser
+	code string
+	// fn the tag function of the open tag, resolved by the parser
+	fn TagFunc
+	// url of the built-in link tag. eg: "<link=https://github.com>"
+	url string
+	// paired with a close or open tag
+	paired bool
+	// closes the number of open tags closed by the close tag. more than 1 on auto close the inner tags
+	closes int
+}
+
+// tagLexer a single-pass lexer for color tags, produce a token stream of text, open tag and close tag.
+//
+// The escaped `\<` is not a tag start, the text token is not unescaped. see tagLexer.text()
+type tagLexer struct {
+	src string
+	pos int
+	// escape the src contains escaped `\<`
+	escape bool
+	// pending tag token after a text token
+	pending tagToken
+	hasPend bool
+}
+
+func newTagLexer(src string) *tagLexer {
+	return &tagLexer{src: src, escape: strings.Contains(src, `\<`)}
+}
+
+// next returns the next token. returns false on the end of string.
+func (lx *tagLexer) next() (tagToken, bool) {
+	if lx.hasPend {
+		lx.hasPend = false
+		lx.pos = lx.pending.end
+		return lx.pending, true
+	}
+
+	src, start := lx.src, lx.pos
+	if start >= len(src) {
+		return tagToken{}, false
+	}
+
+	for i := start; i < len(src); {
+		j := strings.IndexByte(src[i:], '<')
+		if j < 0 {
+			break
+		}
+		j += i
+
+		tok, ok := lx.lexTag(j)
+		if !ok {
+			i = j + 1
+			continue
+		}
+
+		// tag at the current position
+		if j == start {
+			lx.pos = tok.end
+			return tok, true
+		}
+
+		// text before the tag
+		lx.pending, lx.hasPend = tok, true
+		lx.pos = j
+		return tagToken{kind: tagText, start: start, end: j}, true
+	}
+
+	lx.pos = len(src)
+	return tagToken{kind: tagText, start: start, end: len(src)}, true
+}
+
+// lexTag lex a tag at the `<` position i.
+func (lx *tagLexer) lexTag(i int) (tok tagToken, ok bool) {
+	src := lx.src
+	if lx.escape && isEscapedAt(src, i) {
+		return
+	}
+
+	tok.kind, tok.start = tagOpen, i
+	k := i + 1
+	if k < len(src) && src[k] == '/' {
+		tok.kind = tagClose
+		k++
+	}
+
+	nameStart := k
+	for k < len(src) && isTagNameChar(src[k]) {
+		k++
+	}
+
+	// the attribute value allow more chars. eg: "<link=https://github.com>"
+	if k > nameStart && strings.IndexByte(src[nameStart:k], '=') > 0 {
+		for k < len(src) && isTagValueChar(src[k]) {
+			k++
+		}
+	}
+
+	if k >= len(src) || src[k] != '>' {
+		return tok, false
+	}
+
+	tok.name, tok.end = src[nameStart:k], k+1
+	return tok, true
+}
+
+// text get the text of the token. the escaped `\<` will be unescaped.
+func (lx *tagLexer) text(tok tagToken) string {
+	if lx.escape {
+		// text is followed by a tag if it is not at the end
+		return unescapeTag(lx.src[tok.start:tok.end], tok.end < len(lx.src))
+	}
+	return lx.src[tok.start:tok.end]
+}
+
+// isTagNameChar check the char is allowed in the tag name or attributes. same as [0-9a-zA-Z_=,;]
+func isTagNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '=' || c == ',' || c == ';'
+}
+
+// isTagValueChar check the char is allowed in the attribute value. it's the URL chars.
+func isTagValueChar(c byte) bool {
+	return isTagNameChar(c) || strings.IndexByte("-._~:/?#[]@!$&'()*+%", c) >= 0
+}
+
+// lexAttrs lex the tag attributes string, call fn for each "key=value" item.
+//
+// attr eg: "fg=white;bg=blue;op=bold,blink". spaces around the key and value are trimmed.
+// pos is the byte offset of the item in attr. for an item without "=", the val is empty.
+func lexAttrs(attr string, fn func(key, val string, pos int)) {
+	var offset int
+	for offset <= len(attr) {
+		item := attr[offset:]
+		next := len(attr) + 1
+		if i := strings.IndexByte(item, ';'); i >= 0 {
+			item, next = item[:i], offset+i+1
+		}
+
+		if key := strings.TrimSpace(item); key != "" {
+			pos := offset + strings.Index(item, key[:1])
+			if i := strings.IndexByte(item, '='); i >= 0 {
+				key = strings.TrimSpace(item[:i])
+				fn(key, strings.TrimSpace(item[i+1:]), pos)
+			} else {
+				fn(key, "", pos)
+			}
+		}
+		offset = next
+	}
+}
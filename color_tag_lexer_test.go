@@ -0,0 +1,58 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestTagLexer_next(t *testing.T) {
+	is := assert.New(t)
+
+	str := `a <info>b</> <fg=red;op=bold>c</> \<d> <e f> </g>`
+	var kinds []tagTokenKind
+	var parts []string
+
+	lx := newTagLexer(str)
+	for tok, ok := lx.next(); ok; tok, ok = lx.next() {
+		kinds = append(kinds, tok.kind)
+		if tok.kind == tagText {
+			parts = append(parts, lx.text(tok))
+		} else {
+			parts = append(parts, tok.name)
+			is.Eq(str[tok.start], byte('<'))
+			is.Eq(str[tok.end-1], byte('>'))
+		}
+	}
+
+	is.Eq([]tagTokenKind{
+		tagText, tagOpen, tagText, tagClose, tagText, tagOpen, tagText, tagClose, tagText, tagClose,
+	}, kinds)
+	is.Eq([]string{"a ", "info", "b", "", " ", "fg=red;op=bold", "c", "", " <d> <e f> ", "g"}, parts)
+
+	// empty
+	_, ok := newTagLexer("").next()
+	is.False(ok)
+
+	// only text
+	lx = newTagLexer("a < b > c")
+	tok, ok := lx.next()
+	is.True(ok)
+	is.Eq(tagText, tok.kind)
+	is.Eq("a < b > c", lx.text(tok))
+	_, ok = lx.next()
+	is.False(ok)
+}
+
+func TestLexAttrs(t *testing.T) {
+	is := assert.New(t)
+
+	var items []string
+	var posList []int
+	lexAttrs("fg = red; bg=blue;;op=bold,blink;=;x=;y", func(key, val string, pos int) {
+		items = append(items, key+":"+val)
+		posList = append(posList, pos)
+	})
+	is.Eq([]string{"fg:red", "bg:blue", "op:bold,blink", ":", "x:", "y:"}, items)
+	is.Eq([]int{0, 10, 19, 33, 35, 38}, posList)
+}
@@ -3,6 +3,8 @@ package color
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/gookit/assert"
@@ -64,6 +66,9 @@ def <info>info text
 }
 
 func TestTagParser_Parse_c16_opt(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
 	s := tagParser.Parse("<fg=mga;op=i>msg</>")
 
 	fmt.Println(s)
@@ -71,6 +76,9 @@ func TestTagParser_Parse_c16_opt(t *testing.T) {
 }
 
 func TestTagParser_Parse_named_rgb_code(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
 	s := tagParser.Parse("<deepskyblue>deepskyblue style msg</>")
 
 	fmt.Println(s)
@@ -78,6 +86,9 @@ func TestTagParser_Parse_named_rgb_code(t *testing.T) {
 }
 
 func TestTagParser_Parse_hex_rgb_c256(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
 	is := assert.New(t)
 	p := NewTagParser()
 
@@ -96,6 +107,226 @@ func TestTagParser_Parse_hex_rgb_c256(t *testing.T) {
 	is.Equal("custom tag: \x1b[38;2;231;178;161;48;5;176;1mhello, welcome\x1b[0m", r)
 }
 
+func TestTagParser_Parse_downgrade(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	ForceSetColorLevel(Level256)
+	s := tagParser.Parse("<fg=fc1cac>msg</>")
+	is.Equal("\x1b[38;5;199mmsg\x1b[0m", s)
+	s = tagParser.Parse("<deepskyblue>msg</>")
+	is.Equal("\x1b[38;5;39mmsg\x1b[0m", s)
+
+	ForceSetColorLevel(Level16)
+	s = tagParser.Parse("<fg=fc1cac>msg</>")
+	is.Equal("\x1b[35mmsg\x1b[0m", s)
+	s = tagParser.Parse("<fg=160;bg=23;op=bold>msg</>")
+	is.Equal("\x1b[31;46;1mmsg\x1b[0m", s)
+}
+
+func TestTagParser_Parse_nested(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	tests := []struct {
+		in, want string
+	}{
+		// restore the enclosing style
+		{"<info>a <red>b</> c</>", "\x1b[0;32ma \x1b[0m\x1b[0;32;31mb\x1b[0m\x1b[0;32m c\x1b[0m"},
+		{"<bold>a <red>b</> c</>", "\x1b[1ma \x1b[0m\x1b[1;31mb\x1b[0m\x1b[1m c\x1b[0m"},
+		{"<bg=blue>x<red>y</></>", "\x1b[44mx\x1b[0m\x1b[44;31my\x1b[0m"},
+		// deep nesting
+		{
+			"<fg=red>1<bg=blue>2<op=bold>3</>4</>5</>",
+			"\x1b[31m1\x1b[0m\x1b[31;44m2\x1b[0m\x1b[31;44;1m3\x1b[0m\x1b[31;44m4\x1b[0m\x1b[31m5\x1b[0m",
+		},
+		{"<red><green><blue>x</></></>", "\x1b[0;31;32;34mx\x1b[0m"},
+		// adjacent tags
+		{"<red>H</><green>I</>", "\x1b[0;31mH\x1b[0m\x1b[0;32mI\x1b[0m"},
+		{"<info><red>a</><green>b</></>", "\x1b[0;32;31ma\x1b[0m\x1b[0;32;32mb\x1b[0m"},
+		// multi-line bodies
+		{"<info>a\n<red>b\nc</>\nd</>", "\x1b[0;32ma\n\x1b[0m\x1b[0;32;31mb\nc\x1b[0m\x1b[0;32m\nd\x1b[0m"},
+		// unknown tags are literal
+		{"<info>a <T> b</>", "\x1b[0;32ma <T> b\x1b[0m"},
+		{"<unknown>a</>", "<unknown>a</>"},
+		// unmatched close tag is literal
+		{"<red>a</> b</>", "\x1b[0;31ma\x1b[0m b</>"},
+		// unclosed open tag is literal
+		{"<info>a <red>b</>", "<info>a \x1b[0;31mb\x1b[0m"},
+		{"<red>a</> <info>b", "\x1b[0;31ma\x1b[0m <info>b"},
+	}
+
+	for _, tt := range tests {
+		is.Eq(tt.want, tagParser.Parse(tt.in), tt.in)
+	}
+
+	// clear tags
+	is.Eq("a b c", ClearTag("<info>a <red>b</> c</>"))
+}
+
+func TestTagParser_Parse_namedClose(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	tests := []struct {
+		in, want string
+	}{
+		{"<info>a</info>", "\x1b[0;32ma\x1b[0m"},
+		{"<fg=red>a</fg=red>", "\x1b[31ma\x1b[0m"},
+		{"<info>a <red>b</red> c</info>", "\x1b[0;32ma \x1b[0m\x1b[0;32;31mb\x1b[0m\x1b[0;32m c\x1b[0m"},
+		// mixed with "</>"
+		{"<info>a <red>b</> c</info>", "\x1b[0;32ma \x1b[0m\x1b[0;32;31mb\x1b[0m\x1b[0;32m c\x1b[0m"},
+		// auto close the inner tags
+		{"<info>a <red>b <bold>c</info> d", "\x1b[0;32ma \x1b[0m\x1b[0;32;31mb \x1b[0m\x1b[0;32;31;1mc\x1b[0m d"},
+		{"<info>a <red>b</info> c</>", "\x1b[0;32ma \x1b[0m\x1b[0;32;31mb\x1b[0m c</>"},
+		// unmatched close tag is literal
+		{"<info>a</red>b</>", "\x1b[0;32ma</red>b\x1b[0m"},
+		{"a</info>", "a</info>"},
+		// unknown tag is literal
+		{"<info>a<nope>b</nope></info>", "\x1b[0;32ma<nope>b</nope>\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		is.Eq(tt.want, tagParser.Parse(tt.in), tt.in)
+	}
+	is.Eq("a b c", ClearTag("<info>a <red>b</red> c</info>"))
+}
+
+func TestTagParser_RegisterTag(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	p := NewTagParser()
+	p.RegisterTag("title", "1;4;36")
+	p.RegisterTag("info", "0;34")
+	is.Eq("\x1b[1;4;36mTitle\x1b[0m", p.Parse("<title>Title</title>"))
+	is.Eq("\x1b[0;34mmsg\x1b[0m", p.Parse("<info>msg</>"))
+	is.NoErr(p.Validate("<title>Title</>"))
+
+	// not affect other parsers
+	is.Eq("<title>Title</>", tagParser.Parse("<title>Title</>"))
+	is.Eq("\x1b[0;32mmsg\x1b[0m", tagParser.Parse("<info>msg</>"))
+}
+
+func TestTagParser_RegisterTagFunc(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	p := NewTagParser()
+	p.RegisterTagFunc("upper", func(body string, attrs map[string]string) string {
+		is.Empty(attrs)
+		return strings.ToUpper(body)
+	})
+	p.RegisterTagFunc("pad", func(body string, attrs map[string]string) string {
+		n, _ := strconv.Atoi(attrs["pad"])
+		return body + strings.Repeat(" ", n-len(body)) + "|"
+	})
+	p.RegisterTagFunc("link", func(body string, attrs map[string]string) string {
+		return body + " (" + attrs["link"] + ")"
+	})
+
+	is.Eq("HELLO", p.Parse("<upper>hello</>"))
+	is.Eq("HELLO world", p.Parse("<upper>hello</upper> world"))
+	is.Eq("ab   |", p.Parse("<pad=5>ab</pad=5>"))
+	is.Eq("home (https://github.com/gookit/color?a=b#c)", p.Parse("<link=https://github.com/gookit/color?a=b#c>home</>"))
+
+	// the enclosing style is applied after the function
+	is.Eq("\x1b[0;32ma \x1b[0m\x1b[0;32mB\x1b[0m\x1b[0;32m c\x1b[0m", p.Parse("<info>a <upper>b</> c</>"))
+	// the body is plain text, the inner tags are not rendered
+	is.Eq("ab  |", p.Parse("<pad=4><red>ab</></>"))
+	is.Eq("XY", p.Parse("<upper>x<red>y</></>"))
+	is.Eq("\x1b[0;32ma \x1b[0m\x1b[0;32mB C D\x1b[0m\x1b[0;32m e\x1b[0m", p.Parse("<info>a <upper>b <red>c</> d</> e</>"))
+	// nested functions
+	is.Eq("AB  |", p.Parse("<pad=4><upper>ab</></>"))
+	// auto close the inner function tag
+	is.Eq("AB  | c", p.Parse("<pad=4><upper>ab</pad=4> c"))
+	// unclosed function tag is literal
+	is.Eq("<upper>ab", p.Parse("<upper>ab"))
+	is.NoErr(p.Validate("<upper>a</> <pad=10>b</> <link=https://github.com>c</>"))
+
+	// disable color, will call the function and clear color tags
+	Enable = false
+	is.Eq("AB c", p.ParseByEnv("<upper><red>ab</></> c"))
+	Enable = true
+
+	// not affect other parsers
+	is.Eq("<upper>hello</>", tagParser.Parse("<upper>hello</>"))
+}
+
+func TestTagParser_Parse_escape(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	tests := []struct {
+		in, want string
+	}{
+		{`List\<T>`, "List<T>"},
+		{`<info>List\<T></>`, "\x1b[0;32mList<T>\x1b[0m"},
+		{`\<red>a</>`, "<red>a</>"},
+		{`<info>\<red>a</></>`, "\x1b[0;32m<red>a\x1b[0m</>"},
+		// escaped backslash, the tag is parsed
+		{`a\\<red>b</>`, "a\\\x1b[0;31mb\x1b[0m"},
+		{`a\\\<red>b</>`, "a\\<red>b</>"},
+		// backslash not before '<' is kept
+		{`<red>C:\dir\\</>`, "\x1b[0;31mC:\\dir\\\x1b[0m"},
+		// the close tag is escaped
+		{`<red>C:\dir\</>`, "<red>C:\\dir</>"},
+		{`C:\dir\ <red>a</>`, "C:\\dir\\ \x1b[0;31ma\x1b[0m"},
+	}
+	for _, tt := range tests {
+		is.Eq(tt.want, tagParser.Parse(tt.in), tt.in)
+	}
+
+	is.Eq("\x1b[0;32mList<T>\x1b[0m", ReplaceTag(`<info>List\<T></>`))
+	is.Eq("\x1b[0;32mList<T>\x1b[0m", Sprintf("<info>%s</>", EscapeTag("List<T>")))
+
+	// clear tags
+	is.Eq("List<T>", ClearTag(`<info>List\<T></>`))
+	// the unmatched close tag is kept, same as Parse
+	is.Eq("a<red>b c</>", ClearTag(`<info>a\<red>b</> c</>`))
+	is.Eq(`a\b`, ClearTag(`a\\<red>b</>`))
+
+	// disable color
+	Enable = false
+	is.Eq("List<T>", Sprint(`<info>List\<T></>`))
+	Enable = true
+}
+
+func TestEscapeTag(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("text", EscapeTag("text"))
+	is.Eq(`List\<T>`, EscapeTag("List<T>"))
+	is.Eq(`\<b>bold\</b>`, EscapeTag("<b>bold</b>"))
+	is.Eq(`a\\\<b>`, EscapeTag(`a\<b>`))
+	is.Eq(`C:\dir\\`, EscapeTag(`C:\dir\`))
+	is.Eq(`C:\dir\\\<a>\\`, EscapeTag(`C:\dir\<a>\`))
+
+	// round trip
+	for _, str := range []string{"List<T>", "<red>a</>", `a\<red>`, `a\\<red>`, `C:\dir\`, `C:\\<dir>`, `a\\`, "a < b > c"} {
+		is.Eq(str, ClearTag("<info>"+EscapeTag(str)+"</>"), str)
+		is.Eq(str, ClearTag(EscapeTag(str) + "<info>a</>")[:len(str)], str)
+	}
+
+	// round trip, no tag follows the escaped text
+	for _, str := range []string{`C:\dir`, `a\<b>\\c`, `\<red>`, "List<T>"} {
+		is.Eq(str+" x", Sprintf("%s x", EscapeTag(str)), str)
+		is.Eq("p "+str+" end", ClearTag("<info>p</> "+EscapeTag(str)+" end"), str)
+		is.Eq("p "+str, ClearTag("<info>p</> "+EscapeTag(str)), str)
+	}
+
+	// wrap the escaped text in tags
+	is.Eq("\x1b[0;32ma\\\x1b[0m", tagParser.Parse("<info>"+EscapeTag(`a\`)+"</>"))
+	is.Eq("\x1b[0;32mC:\\<dir>\x1b[0m", tagParser.Parse("<info>"+EscapeTag(`C:\<dir>`)+"</>"))
+	is.Eq("\x1b[0;32mC:\\dir\\\x1b[0m \x1b[1mb\x1b[0m", tagParser.Parse("<info>"+EscapeTag(`C:\dir\`)+"</> <bold>b</>"))
+}
+
 func TestParseCodeFromAttr_basic(t *testing.T) {
 	is := assert.New(t)
 
@@ -151,6 +382,57 @@ func TestParseCodeFromAttr_hex_rgb(t *testing.T) {
 	is.Equal("48;2;231;178;161", s)
 }
 
+func TestParseCodeFromAttr_extended(t *testing.T) {
+	is := assert.New(t)
+
+	// CSS color name
+	is.Eq("38;2;240;248;255", ParseCodeFromAttr("fg=aliceblue"))
+	is.Eq("48;2;0;191;255", ParseCodeFromAttr("bg=DeepSkyBlue"))
+	// 16 color name is preferred
+	is.Eq("31", ParseCodeFromAttr("fg=red"))
+	is.Eq("47", ParseCodeFromAttr("bg=white"))
+	is.Eq("37;47", ParseCodeFromAttr("fg=white;bg=white"))
+
+	// rgb(), hsl(), #hex
+	is.Eq("38;2;1;2;3", ParseCodeFromAttr("fg=rgb(1,2,3)"))
+	is.Eq("48;2;51;119;153", ParseCodeFromAttr("bg=hsl(200,50,40)"))
+	is.Eq("38;2;255;0;204", ParseCodeFromAttr("fg=#f0c"))
+	is.Eq("38;2;231;178;161", ParseCodeFromAttr("fg=#e7b2a1"))
+	is.Eq("", ParseCodeFromAttr("fg=rgb(1,2,300)"))
+	is.Eq("", ParseCodeFromAttr("bg=hsl(400,50,40)"))
+
+	// underline style and color
+	is.Eq("4:3", ParseCodeFromAttr("ul=curly"))
+	is.Eq("4:3;58;2;255;0;0", ParseCodeFromAttr("ul=curly;ulc=#ff0000"))
+	is.Eq("58;5;1", ParseCodeFromAttr("ulc=red"))
+	is.Eq("58;5;12", ParseCodeFromAttr("ulc=hiBlue"))
+	is.Eq("58;5;167", ParseCodeFromAttr("ulc=167"))
+	is.Eq("59", ParseCodeFromAttr("ulc=default"))
+	is.Eq("", ParseCodeFromAttr("ul=zigzag"))
+
+	// all SGR options
+	is.Eq("2;8;21;53;22;29", ParseCodeFromAttr("op=dim,hidden,dul,overline,nobold,nostrike"))
+}
+
+func TestTagParser_Parse_extendedAttr(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := tagParser.Parse("<fg=aliceblue;ul=curly;ulc=#f00>a</>")
+	is.Eq("\x1b[38;2;240;248;255;4:3;58;2;255;0;0ma\x1b[0m", s)
+
+	s = tagParser.Parse("<bg=hsl(200,50,40);op=overline>a</>")
+	is.Eq("\x1b[48;2;51;119;153;53ma\x1b[0m", s)
+
+	// downgrade, the underline color is dropped and the style is plain underline on 16 colors
+	ForceSetColorLevel(Level16)
+	s = tagParser.Parse("<fg=rgb(197,30,20);ul=curly;ulc=#f00>a</>")
+	is.Eq("\x1b[31;4ma\x1b[0m", s)
+	s = tagParser.Parse("<fg=46>a</> <fg=231;bg=16>b</>")
+	is.Eq("\x1b[92ma\x1b[0m \x1b[97;40mb\x1b[0m", s)
+}
+
 func TestPrint(t *testing.T) {
 	is := assert.New(t)
 
@@ -253,10 +535,20 @@ def <info>info text
 	is.Contains(ret, "def info")
 	is.NotContains(ret, "</>")
 
+	// the unknown and unclosed tags are kept as literal text, same as Parse
 	str = "abc <err>text</> def<d>"
 	ret = ClearTag(str)
-	is.Equal("abc text def", ret)
+	is.Equal("abc text def<d>", ret)
 	is.NotContains(ret, "<err>")
+	is.Equal("List<T> a <red>b", ClearTag("List<T> <info>a</> <red>b"))
+
+	// same output with color disabled
+	forceOpenColorRender()
+	defer resetColorRender()
+	Disable()
+	is.Equal("List<T> a", Sprintf("List<T> <info>%s</>", "a"))
+	is.Equal("List<T> a", Render("List<T> <info>a</>"))
+	Enable = true
 }
 
 func TestTag_Print(t *testing.T) {
@@ -0,0 +1,223 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errors for validate color tags. can use errors.Is() to check the TagError.
+var (
+	ErrUnknownTag     = errors.New("unknown color tag")
+	ErrUnclosedTag    = errors.New("unclosed color tag")
+	ErrUnmatchedClose = errors.New("unmatched close tag")
+	// ErrMismatchedClose the close tag name is not same as the last open tag. eg: "<info>a <red>b</info>"
+	ErrMismatchedClose = errors.New("mismatched close tag")
+	ErrInvalidAttr     = errors.New("invalid tag attribute")
+	ErrInvalidColor    = errors.New("invalid color value")
+	ErrInvalidHex      = errors.New("invalid hex color")
+	ErrInvalidRGB      = errors.New("invalid rgb color")
+	ErrInvalidHSL      = errors.New("invalid hsl color")
+	Err256OutOfRange   = errors.New("256 color value out of range 0-255")
+	ErrUnknownOption   = errors.New("unknown option")
+)
+
+// TagError an error on validate color tags.
+type TagError struct {
+	// Offset the byte offset in the string
+	Offset int
+	// Tag the full tag text. eg: "<fg=notacolor>"
+	Tag string
+	// Value the invalid tag name or attribute value
+	Value string
+	// Err the error kind. eg: ErrUnknownTag
+	Err error
+}
+
+// Error string
+func (e *TagError) Error() string {
+	return fmt.Sprintf("offset %d: %s %q in tag %s", e.Offset, e.Err.Error(), e.Value, e.Tag)
+}
+
+// Unwrap returns the error kind
+func (e *TagError) Unwrap() error { return e.Err }
+
+// TagErrors list of the TagError
+type TagErrors []*TagError
+
+// Error string
+func (es TagErrors) Error() string {
+	ss := make([]string, 0, len(es))
+	for _, e := range es {
+		ss = append(ss, e.Error())
+	}
+	return strings.Join(ss, "\n")
+}
+
+// Validate check the color tags in the string, returns TagErrors if has invalid tags.
+//
+// Will report:
+//   - unknown tag name. eg: "<unknwon>text</>"
+//   - unclosed tag, unmatched close tag "</>" and mismatched close tag name
+//   - the invalid open tag is reported once, it is not reported as unclosed. eg: "List<T>"
+//   - invalid attributes. eg: invalid hex, 256 value over 255, unknown op value
+//
+// Usage:
+//
+//	if err := color.NewTagParser().Validate(tpl); err != nil {
+//		for _, e := range err.(color.TagErrors) {
+//			fmt.Println(e.Offset, e.Err)
+//		}
+//	}
+func (tp *TagParser) Validate(str string) error {
+	var es TagErrors
+	// stack of the open tags
+	var stack []tagToken
+	// the invalid open tags, key is the offset. they are reported once, not as unclosed
+	bad := make(map[int]bool)
+
+	lx := newTagLexer(str)
+	for tok, ok := lx.next(); ok; tok, ok = lx.next() {
+		tagStr := str[tok.start:tok.end]
+
+		switch tok.kind {
+		case tagText:
+			continue
+		case tagClose:
+			n := len(stack)
+			if n == 0 {
+				es = append(es, &TagError{Offset: tok.start, Tag: tagStr, Value: tagStr, Err: ErrUnmatchedClose})
+				continue
+			}
+
+			// "</>" or the name is same as the last open tag
+			if tok.name == "" || tok.name == stack[n-1].name {
+				stack = stack[:n-1]
+				continue
+			}
+
+			es = append(es, &TagError{Offset: tok.start, Tag: tagStr, Value: tok.name, Err: ErrMismatchedClose})
+			// same as the lenient mode: auto close the inner tags
+			for k := n - 2; k >= 0; k-- {
+				if stack[k].name == tok.name {
+					stack = stack[:k]
+					break
+				}
+			}
+		default: // open tag
+			stack = append(stack, tok)
+			if code, fn := tp.resolveTag(tok.name); fn != nil || code != "" && !strings.ContainsRune(tok.name, '=') {
+				continue
+			}
+			if linkTagURL(tok.name) != "" {
+				continue
+			}
+
+			n := len(es)
+			if strings.ContainsRune(tok.name, '=') {
+				es = tp.validateAttrs(es, tok, tagStr)
+			} else {
+				es = append(es, &TagError{Offset: tok.start, Tag: tagStr, Value: tok.name, Err: ErrUnknownTag})
+			}
+			bad[tok.start] = len(es) > n
+		}
+	}
+
+	for _, tok := range stack {
+		if bad[tok.start] {
+			continue
+		}
+
+		tagStr := str[tok.start:tok.end]
+		es = append(es, &TagError{Offset: tok.start, Tag: tagStr, Value: tok.name, Err: ErrUnclosedTag})
+	}
+
+	if len(es) == 0 {
+		return nil
+	}
+
+	// sort by offset. the unclosed tags are appended at the end.
+	for i := 1; i < len(es); i++ {
+		for j := i; j > 0 && es[j].Offset < es[j-1].Offset; j-- {
+			es[j], es[j-1] = es[j-1], es[j]
+		}
+	}
+	return es
+}
+
+// ValidateTag check the color tags in the string by the default tag parser. see TagParser.Validate()
+func ValidateTag(str string) error { return tagParser.Validate(str) }
+
+// ParseStrict validate the color tags, then parse and render the string.
+// returns empty string and TagErrors if has invalid tags. see Validate()
+func (tp *TagParser) ParseStrict(str string) (string, error) {
+	if err := tp.Validate(str); err != nil {
+		return "", err
+	}
+	return tp.Parse(str), nil
+}
+
+// validate the attributes of the open tag. eg: "<fg=red;bg=blue;op=bold>"
+func (tp *TagParser) validateAttrs(es TagErrors, tok tagToken, tagStr string) TagErrors {
+	// tok.start + len("<")
+	base := tok.start + 1
+
+	lexAttrs(tok.name, func(key, val string, pos int) {
+		if val == "" {
+			es = append(es, &TagError{Offset: base + pos, Tag: tagStr, Value: key, Err: ErrInvalidAttr})
+			return
+		}
+
+		// offset of the value in the string
+		item := tok.name[pos:]
+		valPos := strings.IndexByte(item, '=')
+		valPos += strings.Index(item[valPos:], val)
+
+		newErr := func(value string, err error) {
+			offset := base + pos
+			if value != key {
+				offset += valPos + strings.Index(val, value)
+			}
+			es = append(es, &TagError{Offset: offset, Tag: tagStr, Value: value, Err: err})
+		}
+
+		switch key {
+		case "fg", "bg", "ulc":
+			if _, err := attrColorCode(key, val); err != nil {
+				newErr(val, err)
+			}
+		case "ul":
+			if _, ok := attrUlStyles[val]; !ok {
+				newErr(val, ErrUnknownOption)
+			}
+		case "op":
+			for _, n := range strings.Split(val, ",") {
+				if _, ok := attrOpts[n]; !ok && n != "" {
+					newErr(n, ErrUnknownOption)
+				}
+			}
+		default:
+			newErr(key, ErrInvalidAttr)
+		}
+	})
+	return es
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isHexDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,89 @@
+package color
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestTagParser_Validate(t *testing.T) {
+	is := assert.New(t)
+	p := NewTagParser()
+
+	// valid
+	is.NoErr(p.Validate("text"))
+	is.NoErr(p.Validate("<info>a <red>b</> c</> <fg=red;bg=23;op=bold,u>d</> <fg=fc1cac;bg=1,2,3>e</>"))
+	is.NoErr(p.Validate(`<info>List\<T></>`))
+	is.NoErr(ValidateTag("<deepskyblue>a</> a < b"))
+	is.NoErr(p.Validate("<info>a <red>b</red> c</> <fg=red>d</fg=red>"))
+	is.NoErr(p.Validate("<fg=aliceblue;bg=hsl(200,50,40);ul=curly;ulc=#f00;op=dim,overline>a</> <fg=rgb(1,2,3)>b</>"))
+
+	tests := []struct {
+		str    string
+		offset int
+		value  string
+		err    error
+	}{
+		{"ab <unknwon>text</>", 3, "unknwon", ErrUnknownTag},
+		{"List<T> a", 4, "T", ErrUnknownTag},
+		{"<fg=notacolor>a", 4, "notacolor", ErrInvalidColor},
+		{"<info>text</></>", 13, "</>", ErrUnmatchedClose},
+		{"a <info>text", 2, "info", ErrUnclosedTag},
+		{"<fg=notacolor>a</>", 4, "notacolor", ErrInvalidColor},
+		{"<fg=red;bg=fc1ca>a</>", 11, "fc1ca", ErrInvalidHex},
+		{"<fg=#ggg>a</>", 4, "#ggg", ErrInvalidHex},
+		{"<fg=256>a</>", 4, "256", Err256OutOfRange},
+		{"<bg=1000>a</>", 4, "1000", Err256OutOfRange},
+		{"<fg=1,2>a</>", 4, "1,2", ErrInvalidRGB},
+		{"<fg=1,2,300>a</>", 4, "1,2,300", ErrInvalidRGB},
+		{"<fg=rgb(1,2,300)>a</>", 4, "rgb(1,2,300)", ErrInvalidRGB},
+		{"<bg=hsl(1,2)>a</>", 4, "hsl(1,2)", ErrInvalidHSL},
+		{"<ul=zigzag>a</>", 4, "zigzag", ErrUnknownOption},
+		{"<ul=curly;ulc=notacolor>a</>", 14, "notacolor", ErrInvalidColor},
+		{"<fg=red;op=bold,bad>a</>", 16, "bad", ErrUnknownOption},
+		{"<fg=red;xx=1>a</>", 8, "xx", ErrInvalidAttr},
+		{"<fg=red;op>a</>", 8, "op", ErrInvalidAttr},
+		{"<info>a <red>b</info>", 14, "info", ErrMismatchedClose},
+		{"<info>a</red></info>", 7, "red", ErrMismatchedClose},
+		{"a</info>", 1, "</info>", ErrUnmatchedClose},
+	}
+
+	for _, tt := range tests {
+		err := p.Validate(tt.str)
+		is.Err(err, tt.str)
+
+		es, ok := err.(TagErrors)
+		is.True(ok)
+		is.Len(es, 1, tt.str)
+		is.Eq(tt.offset, es[0].Offset, tt.str)
+		is.Eq(tt.value, es[0].Value, tt.str)
+		is.True(errors.Is(es[0], tt.err), tt.str)
+	}
+
+	// multi errors, sorted by offset
+	err := p.Validate("<bad>a <fg=999>b</> c")
+	is.Err(err)
+	es := err.(TagErrors)
+	is.Len(es, 2)
+	is.Eq(0, es[0].Offset)
+	is.True(errors.Is(es[0], ErrUnknownTag))
+	is.Eq(11, es[1].Offset)
+	is.True(errors.Is(es[1], Err256OutOfRange))
+	is.Eq(`offset 11: 256 color value out of range 0-255 "999" in tag <fg=999>`, es[1].Error())
+	is.Contains(err.Error(), "offset 0: unknown color tag \"bad\" in tag <bad>\n")
+}
+
+func TestTagParser_ParseStrict(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s, err := tagParser.ParseStrict("<info>a</>")
+	is.NoErr(err)
+	is.Eq("\x1b[0;32ma\x1b[0m", s)
+
+	s, err = tagParser.ParseStrict("<info>a</> <T>")
+	is.Err(err)
+	is.Eq("", s)
+}
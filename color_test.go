@@ -104,7 +104,12 @@ func TestSet(t *testing.T) {
 			fmt.Println("- IsTerminal return FALSE")
 		}
 	} else {
-		is.True(IsTerminal(os.Stdout.Fd()))
+		// stdout maybe redirected on run tests, so only check the pipe.
+		r, w, err := os.Pipe()
+		is.NoErr(err)
+		is.False(IsTerminal(w.Fd()))
+		_ = r.Close()
+		_ = w.Close()
 		// is.False(IsLikeInCmd())
 		is.Empty(InnerErrs())
 	}
@@ -225,6 +230,33 @@ func TestRenderCode(t *testing.T) {
 	is.Empty(InnerErrs())
 }
 
+func TestRenderCode_downgrade(t *testing.T) {
+	buf := forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	ForceSetColorLevel(Level256)
+	is.Eq("\x1b[38;5;173mMSG\x1b[0m", RenderCode("38;2;204;123;56", "MSG"))
+	is.Eq("\x1b[38;5;173mMSG\x1b[0m", RenderString("38;2;204;123;56", "MSG"))
+	is.Eq("\x1b[38;5;173mM G\x1b[0m", RenderWithSpaces("38;2;204;123;56", "M", "G"))
+	is.Eq("\x1b[38;5;173;1mMSG\x1b[0m", NewRGBStyle(RGB(204, 123, 56)).AddOpts(OpBold).Sprint("MSG"))
+
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[31mMSG\x1b[0m", RGB(197, 30, 20).Sprint("MSG"))
+	is.Eq("\x1b[31;46mMSG\x1b[0m", S256(160, 23).Sprint("MSG"))
+	is.Eq("\x1b[41mMSG\x1b[0m", C256(160, true).Sprintf("%s", "MSG"))
+
+	// print
+	RGB(197, 30, 20).Print("MSG")
+	is.Eq("\x1b[31mMSG\x1b[0m", buf.String())
+	buf.Reset()
+
+	// set terminal
+	is.NoErr(C256(160).Set())
+	is.Eq("\x1b[31m", buf.String())
+	buf.Reset()
+}
+
 func TestClearCode(t *testing.T) {
 	is := assert.New(t)
 
@@ -235,6 +267,12 @@ func TestClearCode(t *testing.T) {
 	// 24bit
 	is.Equal("Text", ClearCode("\x1b[38;2;30;144;255mText\x1b[0m"))
 	is.Equal("Text other", ClearCode("\033[36;1mText\x1b[0m other"))
+	// without reset code
+	is.Equal("Text", ClearCode("\x1b[36mText"))
+	// other escape sequences
+	is.Equal("Text", ClearCode("\x1b[2K\x1b[1ATe\x1b[4:3mxt"))
+	is.Equal("link", ClearCode("\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"))
+	is.Equal("link", ClearCode("\x1b]8;id=1;https://example.com\alink\x1b]8;;\a"))
 }
 
 /*************************************************************
@@ -359,6 +397,30 @@ func TestColor16(t *testing.T) {
 	is.True(ok)
 }
 
+func TestColor_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	buf := FgGreen.AppendRender([]byte("log: "), "msg")
+	is.Eq("log: "+FgGreen.Render("msg"), string(buf))
+	is.Eq("log: ", string(FgGreen.AppendRender(buf[:5], "")))
+	is.Eq("107", BgLightWhite.String())
+
+	// re-apply the color after each reset
+	s := FgRed.Render("b")
+	is.Eq(RenderString("32", "a"+s+"c"), string(FgGreen.AppendRender(nil, "a"+s+"c")))
+
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = FgGreen.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+
+	Disable()
+	is.Eq("ab", string(FgGreen.AppendRender(nil, "a"+FgRed.Render("b"))))
+	Enable = true
+}
+
 func TestColor_check(t *testing.T) {
 	assert.True(t, Cyan.IsValid())
 	assert.True(t, BgCyan.IsValid())
@@ -608,6 +670,33 @@ func TestColor256_set_reset(t *testing.T) {
 	fmt.Println()
 }
 
+func TestColor256_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	c := C256(132, true)
+	is.Eq("48;5;132", c.String())
+	is.Eq("132", c.Code())
+	is.Eq(c.Sprint("msg"), string(c.AppendRender(nil, "msg")))
+	is.Eq("", string(Color256{12, 2}.AppendRender(nil, "")))
+	is.Eq("msg", string(Color256{12, 2}.AppendRender(nil, "msg")))
+
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[41mmsg\x1b[0m", string(C256(160, true).AppendRender(nil, "msg")))
+	is.Eq("\x1b[91mmsg\x1b[0m", string(C256(196).AppendRender(nil, "msg")))
+	is.Eq("\x1b[30mmsg\x1b[0m", string(C256(16).AppendRender(nil, "msg")))
+	is.Eq("\x1b[31mmsg\x1b[0m", string(RGB(200, 30, 30).AppendRender(nil, "msg")))
+	is.Eq("\x1b[91mmsg\x1b[0m", RenderCode("38;5;196", "msg"))
+	ForceSetColorLevel(LevelRgb)
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = c.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+}
+
 func TestColor256_AsBg(t *testing.T) {
 	is := assert.New(t)
 	c := C256(132)
@@ -702,6 +791,24 @@ func TestStyle256(t *testing.T) {
 	is.Equal("\x1b[38;5;132mMSG\x1b[0m\n", str)
 }
 
+func TestStyle256_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := S256(192, 38).AddOpts(OpBold)
+	is.Eq("38;5;192;48;5;38;1", s.String())
+	is.Eq(s.Sprint("msg"), string(s.AppendRender(nil, "msg")))
+	is.Eq("48;5;38", S256().SetBg(38).String())
+	is.Eq("msg", string(S256().AppendRender(nil, "msg")))
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = s.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+}
+
 func TestPrint256color(t *testing.T) {
 	fmt.Printf("\n%-50s24th Order Grayscale Color\n", " ")
 
@@ -732,6 +839,68 @@ func TestOpts_Add(t *testing.T) {
 
 	op.Add(OpUnderscore)
 	is.Equal("1;5;4", op.String())
+
+	// extended options
+	op = Opts{}
+	op.Add(OpOverline, OpDoubleUnderline, Color(22), FgRed)
+	is.Equal("53;21;22", op.String())
+}
+
+func TestColor_underlineStyle(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("4:0", UnderlineNone.Code())
+	is.Eq("4:3", UnderlineCurly.String())
+	is.Eq("4:5", UnderlineDashed.Code())
+	is.True(UnderlineDotted.IsValid())
+	is.False(UnderlineStyle(0).IsValid())
+	is.Eq("", UnderlineStyle(0).Code())
+	is.False(UnderlineStyle(7).IsValid())
+	is.Eq("53", OpOverline.Code())
+
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	s := Style{FgRed}.WithUlStyle(UnderlineCurly)
+	is.Eq("\x1b[31;4:3mMSG\x1b[0m", s.Sprint("MSG"))
+	is.Eq("\x1b[4:4mMSG\x1b[0m", MixStyle{UlStyle: UnderlineDotted}.Sprint("MSG"))
+
+	// downgrade to plain underline
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[31;4mMSG\x1b[0m", s.Sprint("MSG"))
+	is.Eq("\x1b[31;4mMSG\x1b[0m", string(s.AppendRender(nil, "MSG")))
+}
+
+func TestColor_extOptionHelpers(t *testing.T) {
+	is := assert.New(t)
+
+	// the extended options are real SGR codes, the Color helpers are not changed
+	for _, op := range []Color{OpDoubleUnderline, OpOverline} {
+		is.True(op.IsValid())
+		is.False(op.IsOption())
+	}
+
+	is.Eq("doubleUnderline", OpDoubleUnderline.Name())
+	is.Eq("overline", OpOverline.Name())
+	is.True(Color(10).IsValid())
+	is.False(Color(200).IsValid())
+	is.Eq("200", Color(200).String())
+}
+
+func TestStyle256_SetUl(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := S256(255).SetUl(196).SetUlStyle(UnderlineCurly)
+	is.Eq("38;5;255;58;5;196;4:3", s.String())
+	is.Eq("\x1b[38;5;255;58;5;196;4:3mMSG\x1b[0m", s.Sprint("MSG"))
+	is.Eq("58;5;9", S256().SetUl(9).String())
+
+	// the underline color is dropped on 16 colors
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[37;4mMSG\x1b[0m", s.Sprint("MSG"))
+	is.Eq("\x1b[37;4mMSG\x1b[0m", string(s.AppendRender(nil, "MSG")))
 }
 
 /*************************************************************
@@ -742,8 +911,7 @@ var oldVal Level
 
 // force open color render for testing
 func forceOpenColorRender() *bytes.Buffer {
-	oldVal = colorLevel
-	ForceOpenColor()
+	oldVal = ForceOpenColor()
 
 	// set output for test
 	buf := new(bytes.Buffer)
@@ -752,7 +920,7 @@ func forceOpenColorRender() *bytes.Buffer {
 }
 
 func resetColorRender() {
-	colorLevel = oldVal
+	ForceSetColorLevel(oldVal)
 	// reset
 	ResetOutput()
 }
@@ -836,6 +1004,10 @@ func mockOsEnvByText(envText string, fn func()) {
 
 func mockOsEnv(mp map[string]string, fn func()) {
 	envBak := os.Environ()
+	// mock the stdout is a terminal
+	termFn := isStdoutTerminal
+	isStdoutTerminal = func() bool { return true }
+	defer func() { isStdoutTerminal = termFn }()
 
 	os.Clearenv()
 	for key, val := range mp {
@@ -408,12 +408,7 @@ func Colors2code(colors ...Color) string {
 		return ""
 	}
 
-	var codes []string
-	for _, color := range colors {
-		codes = append(codes, color.String())
-	}
-
-	return strings.Join(codes, ";")
+	return string(Opts(colors).appendCode(make([]byte, 0, 16), 0))
 }
 
 /*************************************************************
@@ -536,7 +531,13 @@ func Rgb2ansi(r, g, b uint8, isBg bool) uint8 {
 // RgbToAnsi convert RGB-code to 16-code
 // refer https://github.com/radareorg/radare2/blob/master/libr/cons/rgb.c#L249-L271
 func RgbToAnsi(r, g, b uint8, isBg bool) uint8 {
-	var bright, c, k uint8
+	// NOTICE: keep the uint8 sum for compatible, the downgrade on render uses rgbToBasic()
+	return rgbToAnsi(r, g, b, isBg, (r+g+b)/3)
+}
+
+// rgbToAnsi convert RGB-code to 16-code by the gray level k
+func rgbToAnsi(r, g, b uint8, isBg bool, k uint8) uint8 {
+	var bright, c uint8
 	base := compareVal(isBg, BgBase, FgBase)
 
 	// eco bright-specific
@@ -553,8 +554,6 @@ func RgbToAnsi(r, g, b uint8, isBg bool) uint8 {
 		g = compareVal(g > 0x7f, 1, 0)
 		b = compareVal(b > 0x7f, 1, 0)
 	} else {
-		k = (r + g + b) / 3
-
 		// r = (r >= k) ? 1 : 0;
 		r = compareVal(r >= k, 1, 0)
 		g = compareVal(g >= k, 1, 0)
@@ -648,6 +647,155 @@ func C256ToRgbV1(val uint8) (rgb []uint8) {
 	return []uint8{r, g, b}
 }
 
+/*************************************************************
+ * region convert code by level
+ * downgrade RGB/256 color code to the supported color level
+ *************************************************************/
+
+// ConvertCodeByLevel convert the color code string to the best code
+// that the given color level supports.
+//
+//   - LevelRgb: return the code without change.
+//   - Level256: RGB color "38;2;r;g;b" will convert to "38;5;n"
+//   - Level16: RGB and 256 color will convert to basic 16 color code. eg: "31"
+//   - underline color "58;2;r;g;b", "58;5;n" convert as above, but will be dropped on Level16
+//   - underline style "4:1" - "4:5" downgrade to plain underline "4" on Level16, "4:0" to "24"
+//
+// Usage:
+//
+//	code := ConvertCodeByLevel("38;2;204;123;56;1", Level256) // "38;5;173;1"
+func ConvertCodeByLevel(code string, level Level) string {
+	// LevelNo: the caller should clear the color codes
+	if level >= LevelRgb || level == LevelNo || code == "" {
+		return code
+	}
+
+	// not contains 256 or RGB color code. eg: "38;5;" "48;2;"
+	hasUlStyle := level == Level16 && strings.Contains(code, "4:")
+	if !strings.Contains(code, "8;") && !hasUlStyle {
+		return code
+	}
+
+	nodes := strings.Split(code, ";")
+	codes := make([]string, 0, len(nodes))
+
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		if hasUlStyle && strings.HasPrefix(node, "4:") {
+			if node == "4:0" {
+				codes = append(codes, "24")
+			} else {
+				codes = append(codes, "4")
+			}
+			continue
+		}
+
+		if (node != "38" && node != "48" && node != "58") || i+2 >= len(nodes) {
+			codes = append(codes, node)
+			continue
+		}
+
+		isBg := node == "48"
+		// underline color "58;5;n" has no 16 color code, drop it.
+		isUl := node == "58"
+		switch nodes[i+1] {
+		case "5": // 256 color: "38;5;n"
+			val, err := strconv.ParseUint(nodes[i+2], 10, 8)
+			if err != nil {
+				codes = append(codes, node)
+				continue
+			}
+
+			i += 2
+			if level == Level256 {
+				codes = append(codes, node, "5", nodes[i])
+			} else if !isUl {
+				codes = append(codes, strconv.Itoa(int(C256ToBasic(uint8(val), isBg))))
+			}
+		case "2": // RGB color: "38;2;r;g;b"
+			if i+4 >= len(nodes) {
+				codes = append(codes, node)
+				continue
+			}
+
+			var rgb [3]uint8
+			var err error
+			for j := 0; j < 3 && err == nil; j++ {
+				var val uint64
+				val, err = strconv.ParseUint(nodes[i+2+j], 10, 8)
+				rgb[j] = uint8(val)
+			}
+			if err != nil {
+				codes = append(codes, node)
+				continue
+			}
+
+			i += 4
+			if level == Level256 {
+				codes = append(codes, node, "5", strconv.Itoa(int(RgbTo256(rgb[0], rgb[1], rgb[2]))))
+			} else if !isUl {
+				codes = append(codes, strconv.Itoa(int(rgbToBasic(rgb[0], rgb[1], rgb[2], isBg))))
+			}
+		default:
+			codes = append(codes, node)
+		}
+	}
+
+	return strings.Join(codes, ";")
+}
+
+// C256ToBasic convert a 256 color code to basic 16 color code.
+//
+// Usage:
+//
+//	C256ToBasic(9, false) // 91
+//	C256ToBasic(160, true) // 41
+func C256ToBasic(val uint8, isBg bool) uint8 {
+	var code uint8
+	switch {
+	case val < 8: // standard colors, same as 30-37
+		code = FgBase + val
+	case val < 16: // high intensity colors, same as 90-97
+		code = HiFgBase + val - 8
+	default:
+		rgb := xterm256ToRgb(val)
+		return rgbToBasic(rgb[0], rgb[1], rgb[2], isBg)
+	}
+
+	if isBg {
+		return Fg2Bg(code)
+	}
+	return code
+}
+
+// rgbToBasic convert RGB to basic 16 color code for the downgrade on render.
+// same as Rgb2basic(), but the gray level is calculated without uint8 overflow.
+func rgbToBasic(r, g, b uint8, isBg bool) uint8 {
+	hex := RgbToHex([]int{int(r), int(g), int(b)})
+	if val, ok := hex2basicMap[hex]; ok {
+		if isBg {
+			return val + 10
+		}
+		return val
+	}
+	return rgbToAnsi(r, g, b, isBg, uint8((int(r)+int(g)+int(b))/3))
+}
+
+// xterm color cube levels of the 256 colors 16 - 231
+var xtermLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// xterm256ToRgb convert the 256 color code 16 - 255 to RGB by the xterm palette.
+// the hexTo256Table has adjusted values for avoid key conflicts. eg: 16 is "000001"
+func xterm256ToRgb(val uint8) [3]uint8 {
+	if val >= 232 { // grayscale ramp
+		v := 8 + (val-232)*10
+		return [3]uint8{v, v, v}
+	}
+
+	i := val - 16
+	return [3]uint8{xtermLevels[i/36], xtermLevels[i/6%6], xtermLevels[i%6]}
+}
+
 /**************************************************************
  * region HSL <=> RGB color
  ************************************************************
@@ -18,6 +18,78 @@ func TestHex2basic(t *testing.T) {
 	assert.Equal(t, uint8(105), Hex2basic("fd7cfc", true))
 }
 
+func TestConvertCodeByLevel(t *testing.T) {
+	is := assert.New(t)
+
+	// no change
+	is.Eq("38;2;204;123;56", ConvertCodeByLevel("38;2;204;123;56", LevelRgb))
+	is.Eq("38;5;173", ConvertCodeByLevel("38;5;173", LevelNo))
+	is.Eq("32;1", ConvertCodeByLevel("32;1", Level16))
+	is.Eq("", ConvertCodeByLevel("", Level16))
+
+	// to 256
+	is.Eq("38;5;173;1", ConvertCodeByLevel("38;2;204;123;56;1", Level256))
+	is.Eq("38;5;173;48;5;23", ConvertCodeByLevel("38;2;204;123;56;48;5;23", Level256))
+	is.Eq("1;48;5;0", ConvertCodeByLevel("1;48;2;0;0;0", Level256))
+
+	// to 16
+	is.Eq("31", ConvertCodeByLevel("38;2;197;30;20", Level16))
+	is.Eq("31;46;1", ConvertCodeByLevel("38;5;160;48;5;23;1", Level16))
+	is.Eq("91;100", ConvertCodeByLevel("38;5;9;48;5;8", Level16))
+	is.Eq("91", ConvertCodeByLevel("38;5;196", Level16))
+	is.Eq("30;107", ConvertCodeByLevel("38;5;16;48;5;231", Level16))
+	is.Eq("92", ConvertCodeByLevel("38;5;46", Level16))
+	is.Eq("31", ConvertCodeByLevel("38;2;200;30;30", Level16))
+
+	// underline color
+	is.Eq("4:3;58;5;9", ConvertCodeByLevel("4:3;58;2;255;0;0", Level256))
+	is.Eq("4;1", ConvertCodeByLevel("4:3;58;2;255;0;0;1", Level16))
+	is.Eq("31", ConvertCodeByLevel("58;5;9;31", Level16))
+
+	// underline style
+	is.Eq("4:3;53", ConvertCodeByLevel("4:3;53", Level256))
+	is.Eq("4;53", ConvertCodeByLevel("4:3;53", Level16))
+	is.Eq("24;31", ConvertCodeByLevel("4:0;31", Level16))
+
+	// invalid code, keep raw value
+	is.Eq("38;5", ConvertCodeByLevel("38;5", Level16))
+	is.Eq("38;2;300;1;1", ConvertCodeByLevel("38;2;300;1;1", Level256))
+}
+
+func TestC256ToBasic(t *testing.T) {
+	assert.Eq(t, uint8(30), C256ToBasic(0, false))
+	assert.Eq(t, uint8(47), C256ToBasic(7, true))
+	assert.Eq(t, uint8(97), C256ToBasic(15, false))
+	assert.Eq(t, uint8(101), C256ToBasic(9, true))
+	assert.Eq(t, uint8(31), C256ToBasic(160, false))
+	assert.Eq(t, uint8(41), C256ToBasic(160, true))
+
+	// the sum of rgb values > 255, eg: 46 is 0,255,0
+	assert.Eq(t, uint8(30), C256ToBasic(16, false))
+	assert.Eq(t, uint8(92), C256ToBasic(46, false))
+	assert.Eq(t, uint8(91), C256ToBasic(196, false))
+	assert.Eq(t, uint8(101), C256ToBasic(196, true))
+	assert.Eq(t, uint8(97), C256ToBasic(231, false))
+}
+
+func TestRgbToBasic(t *testing.T) {
+	is := assert.New(t)
+
+	// the sum of r, g, b is greater than 255
+	is.Eq(uint8(45), rgbToBasic(170, 78, 204, true))
+	is.Eq(uint8(34), rgbToBasic(170, 153, 245, false))
+	is.Eq(uint8(94), rgbToBasic(34, 56, 255, false))
+	is.Eq(uint8(31), rgbToBasic(200, 30, 30, false))
+	is.Eq(uint8(43), rgbToBasic(200, 200, 30, true))
+	is.Eq(uint8(37), rgbToBasic(204, 204, 204, false))
+
+	is.Eq([3]uint8{0, 0, 0}, xterm256ToRgb(16))
+	is.Eq([3]uint8{255, 0, 0}, xterm256ToRgb(196))
+	is.Eq([3]uint8{255, 135, 0}, xterm256ToRgb(208))
+	is.Eq([3]uint8{128, 128, 128}, xterm256ToRgb(244))
+	is.Eq([3]uint8{238, 238, 238}, xterm256ToRgb(255))
+}
+
 func TestHslToRgb(t *testing.T) {
 	// red #ff0000	255,0,0  0,100%,50%
 	rgbVal := HslToRgb(0, 1, 0.5)
@@ -2,7 +2,9 @@ package color
 
 import (
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"runtime"
 	"strconv"
 	"strings"
@@ -36,25 +38,315 @@ func DetectColorLevel() Level {
 	return level
 }
 
+// env names for control the color output. see DetectColorLevelSource() for the precedence.
+const (
+	// EnvNoColor if not empty, disable color output. see https://no-color.org
+	EnvNoColor = "NO_COLOR"
+	// EnvForceColor force color output. value 0/false: disable, 1/2/3: use Level16/Level256/LevelRgb,
+	// other non-empty value(eg: on, true): force enable and detect level from env.
+	EnvForceColor = "FORCE_COLOR"
+	// EnvCliColor value is 0, disable color output. see https://bixense.com/clicolors
+	EnvCliColor = "CLICOLOR"
+	// EnvCliColorForce not empty and not 0, force color output even if stdout is not a terminal.
+	EnvCliColorForce = "CLICOLOR_FORCE"
+	// EnvForceHyperlink value is 0, disable the OSC 8 hyperlinks, other non-empty value force enable it.
+	EnvForceHyperlink = "FORCE_HYPERLINK"
+)
+
+// SourceTTY the source name on the color level is decided by stdout is not a terminal.
+const SourceTTY = "TTY"
+
+// isStdoutTerminal check the os.Stdout is a terminal. can be mocked on tests.
+var isStdoutTerminal = func() bool {
+	return IsTerminal(os.Stdout.Fd())
+}
+
+// DetectColorLevelSource detect color level for current env, and returns
+// the source(env name or SourceTTY) that decided the level.
+//
+// The precedence order on detect:
+//
+//  1. NO_COLOR not empty: LevelNo
+//  2. FORCE_COLOR=0/false: LevelNo, FORCE_COLOR=1/2/3: Level16/Level256/LevelRgb
+//  3. FORCE_COLOR other value(eg: on) or CLICOLOR_FORCE not 0: force enable, skip 4 and 5
+//  4. CLICOLOR=0: LevelNo
+//  5. stdout is not a terminal(eg: redirect to file or pipe): LevelNo, source is SourceTTY
+//  6. detect by terminal env: WSL_DISTRO_NAME, TERMINAL_EMULATOR, COLORTERM, TERM_PROGRAM, TERM.
+//     on force enable, the level is at least Level16.
+//
+// Usage:
+//
+//	level, source := color.DetectColorLevelSource()
+//	fmt.Printf("color level %s is decided by %s\n", level, source)
+func DetectColorLevelSource() (level Level, source string) {
+	level, _, source = detectColorLevelWithSource()
+	return
+}
+
 // detect terminal color support level
+func detectTermColorLevel() (level Level, needVTP bool) {
+	level, needVTP, _ = detectColorLevelWithSource()
+	return
+}
+
+// detect color level and returns the source that decided the level.
+func detectColorLevelWithSource() (level Level, needVTP bool, source string) {
+	d := newDetector()
+	level, needVTP = d.detect()
+	return level, needVTP, d.source
+}
+
+// detector detect the color level and record the inspected signals.
+type detector struct {
+	isWin bool
+	// inspected signals, in order of inspected
+	signals []DetectionSignal
+	// source the signal name and rule that decided the level
+	source, rule string
+	// errors on detect
+	errs []error
+	// saveErrs save errors to the global innerErrs
+	saveErrs bool
+	// lookupEnv, fsys and isTerminal are from DetectOptions
+	lookupEnv  func(key string) (string, bool)
+	fsys       fs.FS
+	isTerminal func() bool
+	// loadTerm load terminfo by TERM name
+	loadTerm func(name string) (*terminfo.Terminfo, error)
+}
+
+// DetectOptions options for detect the color level.
+//
+// The zero value will use the current process env, filesystem and os.Stdout.
+// Custom them can simulate any terminal profile without touching global state.
+//
+// Usage:
+//
+//	level := color.DetectColorLevelWith(color.DetectOptions{
+//		LookupEnv: func(key string) (string, bool) {
+//			val, ok := map[string]string{"TERM_PROGRAM": "Apple_Terminal"}[key]
+//			return val, ok
+//		},
+//		FS:         fstest.MapFS{},
+//		IsTerminal: func() bool { return true },
+//	})
+type DetectOptions struct {
+	// LookupEnv lookup env value by key. default is os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+	// FS the root filesystem, use for read /proc/version and terminfo files.
+	// default will read from the OS filesystem.
+	FS fs.FS
+	// IsTerminal check the stdout is a terminal. default check the os.Stdout
+	IsTerminal func() bool
+}
+
+// new detector for current process, will save errors to the innerErrs.
+func newDetector() *detector {
+	d := newDetectorWith(DetectOptions{})
+	d.saveErrs = true
+	return d
+}
+
+func newDetectorWith(opts DetectOptions) *detector {
+	d := &detector{
+		isWin:      runtime.GOOS == "windows",
+		lookupEnv:  opts.LookupEnv,
+		fsys:       opts.FS,
+		isTerminal: opts.IsTerminal,
+	}
+
+	if d.lookupEnv == nil {
+		d.lookupEnv = os.LookupEnv
+	}
+	if d.isTerminal == nil {
+		d.isTerminal = isStdoutTerminal
+	}
+
+	if d.fsys == nil {
+		d.fsys = os.DirFS("/")
+		// use the terminfo.Load() for cache the loaded terminfo
+		d.loadTerm = terminfo.Load
+	} else {
+		d.loadTerm = d.loadTerminfo
+	}
+	return d
+}
+
+// DetectColorLevelWith detect color level by the options.
+func DetectColorLevelWith(opts DetectOptions) Level {
+	level, _ := newDetectorWith(opts).detect()
+	return level
+}
+
+// getenv get env value and record it as a signal.
+func (d *detector) getenv(name string) string {
+	for _, s := range d.signals {
+		if s.Name == name {
+			return s.Value
+		}
+	}
+
+	val, ok := d.lookupEnv(name)
+	d.signals = append(d.signals, DetectionSignal{Name: name, Value: val, Set: ok})
+	return val
+}
+
+// addSignal record a signal that not from env. eg: TTY, terminfo
+func (d *detector) addSignal(name, value string) {
+	d.signals = append(d.signals, DetectionSignal{Name: name, Value: value, Set: true})
+}
+
+// decide record the signal and rule that decided the level.
+func (d *detector) decide(name, rule string) {
+	debugf("decided by %s: %s", name, rule)
+	d.source, d.rule = name, rule
+
+	for i := len(d.signals) - 1; i >= 0; i-- {
+		if d.signals[i].Name == name {
+			d.signals[i].Rule = rule
+			break
+		}
+	}
+}
+
+func (d *detector) addError(err error) {
+	if d.saveErrs {
+		saveInternalError(err)
+	} else {
+		debugf("detect error: %s", err.Error())
+	}
+	d.errs = append(d.errs, err)
+}
+
+// detect color level by the standard env NO_COLOR, FORCE_COLOR, CLICOLOR, CLICOLOR_FORCE
+// and the terminal env.
+func (d *detector) detect() (level Level, needVTP bool) {
+	if d.getenv(EnvNoColor) != "" {
+		d.decide(EnvNoColor, "NO_COLOR is not empty, disable color")
+		return LevelNo, false
+	}
+
+	var forceBy string
+	if val := d.getenv(EnvForceColor); val != "" {
+		if lv, ok := parseForceColor(val); ok {
+			d.decide(EnvForceColor, "FORCE_COLOR="+val+", use level "+lv.String())
+			return lv, d.isWin && lv > LevelNo
+		}
+		forceBy = EnvForceColor
+	} else if val := d.getenv(EnvCliColorForce); val != "" && val != "0" {
+		forceBy = EnvCliColorForce
+	}
+
+	if forceBy == "" {
+		if d.getenv(EnvCliColor) == "0" {
+			d.decide(EnvCliColor, "CLICOLOR=0, disable color")
+			return LevelNo, false
+		}
+
+		// stdout is redirected to a file or pipe, dont output color codes.
+		if !d.isWin {
+			isTerm := d.isTerminal()
+			d.addSignal(SourceTTY, strconv.FormatBool(isTerm))
+			if !isTerm {
+				d.decide(SourceTTY, "stdout is not a terminal, disable color")
+				return LevelNo, false
+			}
+		}
+	}
+
+	level, needVTP = d.detectTermEnv()
+	if forceBy != "" && level == LevelNo {
+		d.decide(forceBy, forceBy+" is set, force enable color")
+		return Level16, d.isWin
+	}
+	return
+}
+
+// DetectLinkWith detect the terminal supports OSC 8 hyperlinks by the options. see Link()
+func DetectLinkWith(opts DetectOptions) bool {
+	return newDetectorWith(opts).detectLink()
+}
+
+// detect the OSC 8 hyperlinks support for current env.
+func detectLinkSupport() bool { return newDetector().detectLink() }
+
+// detect the terminal supports OSC 8 hyperlinks by the env FORCE_HYPERLINK and terminal env.
+//
+// refer https://github.com/Alhadis/OSC8-Adoption
+func (d *detector) detectLink() bool {
+	if val := d.getenv(EnvForceHyperlink); val != "" {
+		return val != "0"
+	}
+
+	if !d.isWin && !d.isTerminal() {
+		return false
+	}
+
+	// Windows Terminal, DomTerm
+	if d.getenv("WT_SESSION") != "" || d.getenv("DOMTERM") != "" {
+		return true
+	}
+
+	switch d.getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty", "Tabby":
+		return true
+	}
+
+	// GNOME Terminal, Tilix ... VTE 0.50+
+	if val := d.getenv("VTE_VERSION"); val != "" {
+		ver, _ := strconv.Atoi(val)
+		return ver >= 5000
+	}
+	// Konsole 20.12+
+	if val := d.getenv("KONSOLE_VERSION"); val != "" {
+		ver, _ := strconv.Atoi(val)
+		return ver >= 201200
+	}
+
+	termVal := d.getenv("TERM")
+	for _, name := range []string{"kitty", "alacritty", "foot", "wezterm", "ghostty"} {
+		if strings.Contains(termVal, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse the FORCE_COLOR value to level. returns false on value is not a level.
+func parseForceColor(val string) (Level, bool) {
+	switch strings.ToLower(val) {
+	case "0", "false":
+		return LevelNo, true
+	case "1":
+		return Level16, true
+	case "2":
+		return Level256, true
+	case "3":
+		return LevelRgb, true
+	}
+	return LevelNo, false
+}
+
+// detect color level by terminal env
 //
 // refer https://github.com/Delta456/box-cli-maker
-func detectTermColorLevel() (level Level, needVTP bool) {
+func (d *detector) detectTermEnv() (level Level, needVTP bool) {
 	// on windows WSL:
 	// - runtime.GOOS == "Linux"
 	// - support true-color
 	// env:
 	// 	WSL_DISTRO_NAME=Debian
-	if val := os.Getenv("WSL_DISTRO_NAME"); val != "" {
+	if val := d.getenv("WSL_DISTRO_NAME"); val != "" {
 		// detect WSL as it has True Color support
-		if detectWSL() {
-			debugf("True Color support on WSL environment")
+		isWSL, contents := detectWSL(d.fsys)
+		d.addSignal("/proc/version", contents)
+		if isWSL {
+			d.decide("WSL_DISTRO_NAME", "True Color support on WSL environment")
 			return LevelRgb, false
 		}
 	}
 
-	isWin := runtime.GOOS == "windows"
-	termVal := os.Getenv("TERM")
+	termVal := d.getenv("TERM")
 
 	// on TERM=screen: not support true-color
 	if termVal != "screen" {
@@ -62,123 +354,173 @@ func detectTermColorLevel() (level Level, needVTP bool) {
 		// - support true-color
 		// env:
 		// 	TERMINAL_EMULATOR=JetBrains-JediTerm
-		val := os.Getenv("TERMINAL_EMULATOR")
+		val := d.getenv("TERMINAL_EMULATOR")
 		if val == "JetBrains-JediTerm" {
-			debugf("True Color support on JetBrains-JediTerm, is win: %v", isWin)
-			return LevelRgb, isWin
+			d.decide("TERMINAL_EMULATOR", "True Color support on JetBrains-JediTerm")
+			return LevelRgb, d.isWin
 		}
 	}
 
 	// level, err = terminfo.ColorLevelFromEnv()
-	level = detectColorLevelFromEnv(termVal, isWin)
-	debugf("color level by detectColorLevelFromEnv: %s", level.String())
+	level = d.detectFromEnv(termVal)
+	debugf("color level by detectFromEnv: %s", level.String())
 
 	// fallback: simple detect by TERM value string.
 	if level == LevelNo {
 		debugf("level none - fallback check special term color support")
 		// on Windows: enable VTP as it has True Color support
-		level, needVTP = detectSpecialTermColor(termVal)
+		level, needVTP = detectSpecialTermColor(d, termVal)
 	}
 	return
 }
 
-// detectColorFromEnv returns the color level COLORTERM, FORCE_COLOR,
+// detectFromEnv returns the color level COLORTERM, FORCE_COLOR,
 // TERM_PROGRAM, or determined from the TERM environment variable.
 //
 // refer the terminfo.ColorLevelFromEnv()
 // https://en.wikipedia.org/wiki/Terminfo
-func detectColorLevelFromEnv(termVal string, isWin bool) Level {
+func (d *detector) detectFromEnv(termVal string) Level {
 	// on TERM=screen: not support true-color
 	if termVal == "screen" {
+		d.decide("TERM", "TERM=screen support 256 colors")
 		return Level256
 	}
 
 	// check for overriding environment variables
-	colorTerm, termProg, forceColor := os.Getenv("COLORTERM"), os.Getenv("TERM_PROGRAM"), os.Getenv("FORCE_COLOR")
+	colorTerm, termProg, forceColor := d.getenv("COLORTERM"), d.getenv("TERM_PROGRAM"), d.getenv(EnvForceColor)
 	switch {
 	case strings.Contains(colorTerm, "truecolor") || strings.Contains(colorTerm, "24bit"):
+		d.decide("COLORTERM", "COLORTERM contains truecolor or 24bit")
 		return LevelRgb
 	case colorTerm != "" || forceColor != "":
+		source := "COLORTERM"
+		if colorTerm == "" {
+			source = EnvForceColor
+		}
 		if strings.Contains(termVal, "256color") {
+			d.decide(source, source+" is set and TERM contains 256color")
 			return Level256
 		}
+		d.decide(source, source+" is set, use basic color")
 		return Level16
 	case termProg == "Apple_Terminal":
+		d.decide("TERM_PROGRAM", "Apple_Terminal support 256 colors")
 		return Level256
 	case termProg == "Terminus" || termProg == "Hyper":
+		d.decide("TERM_PROGRAM", termProg+" support True Color")
 		return LevelRgb
 	case termProg == "iTerm.app":
 		// check iTerm version
-		ver := os.Getenv("TERM_PROGRAM_VERSION")
+		ver := d.getenv("TERM_PROGRAM_VERSION")
 		if ver != "" {
 			i, err := strconv.Atoi(strings.Split(ver, ".")[0])
 			if err != nil {
-				saveInternalError(terminfo.ErrInvalidTermProgramVersion)
+				d.addError(terminfo.ErrInvalidTermProgramVersion)
+				d.decide("TERM_PROGRAM_VERSION", "invalid iTerm version, use 256 colors")
 				// return terminfo.ColorLevelNone
 				return Level256
 			}
 			if i == 3 {
+				d.decide("TERM_PROGRAM_VERSION", "iTerm 3 support True Color")
 				return LevelRgb
 			}
 		}
+		d.decide("TERM_PROGRAM", "iTerm.app support 256 colors")
 		return Level256
 	}
 
 	// otherwise determine from TERM's max_colors capability
-	if !isWin && termVal != "" {
+	if !d.isWin && termVal != "" {
 		debugf("TERM=%s - check color level by load terminfo file", termVal)
-		ti, err := terminfo.Load(termVal)
+		ti, err := d.loadTerm(termVal)
 		if err != nil {
-			saveInternalError(err)
+			d.addError(err)
+			d.decide("TERM", "load terminfo failed")
 			return LevelNo
 		}
 
 		debugf("the loaded term info file is: %s", ti.File)
+		d.addSignal("terminfo", ti.File)
+
 		v, ok := ti.Nums[terminfo.MaxColors]
+		d.addSignal("max_colors", strconv.Itoa(v))
 		switch {
 		case !ok || v <= 16:
+			d.decide("TERM", "terminfo max_colors <= 16")
 			return LevelNo
 		case ok && v >= 256:
+			d.decide("TERM", "terminfo max_colors >= 256")
 			return Level256
 		}
+		d.decide("TERM", "terminfo max_colors > 16")
 		return Level16
 	}
 
 	// no TERM env value. default return none level
+	d.decide("TERM", "TERM is empty")
 	return LevelNo
 	// return terminfo.ColorLevelBasic
 }
 
-var detectedWSL bool
-var wslContents string
-
 // https://github.com/Microsoft/WSL/issues/423#issuecomment-221627364
-func detectWSL() bool {
-	if !detectedWSL {
-		detectedWSL = true
-
-		b := make([]byte, 1024)
-		// `cat /proc/version`
-		// on Mac, Windows cmd/pwsh:
-		// 	!NOT THE FILE!
-		// on linux(debian,ubuntu,alpine):
-		//	Linux version 4.19.121-linuxkit (root@18b3f92ade35) (gcc version 9.2.0 (Alpine 9.2.0)) #1 SMP Thu Jan 21 15:36:34 UTC 2021
-		// on Win git bash, conEmu:
-		// 	MINGW64_NT-10.0-19042 version 3.1.7-340.x86_64 (@WIN-N0G619FD3UK) (gcc version 9.3.0 (GCC) ) 2020-10-23 13:08 UTC
-		// on WSL:
-		//  Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com) (gcc version 5.4.0 (GCC) ) #488-Microsoft Mon Sep 01 13:43:00 PST 2020
-		f, err := os.Open("/proc/version")
-		if err == nil {
-			_, _ = f.Read(b) // ignore error
-			if err = f.Close(); err != nil {
-				saveInternalError(err)
+//
+// returns the contents of the /proc/version file.
+func detectWSL(fsys fs.FS) (bool, string) {
+	// `cat /proc/version`
+	// on Mac, Windows cmd/pwsh:
+	// 	!NOT THE FILE!
+	// on linux(debian,ubuntu,alpine):
+	//	Linux version 4.19.121-linuxkit (root@18b3f92ade35) (gcc version 9.2.0 (Alpine 9.2.0)) #1 SMP Thu Jan 21 15:36:34 UTC 2021
+	// on Win git bash, conEmu:
+	// 	MINGW64_NT-10.0-19042 version 3.1.7-340.x86_64 (@WIN-N0G619FD3UK) (gcc version 9.3.0 (GCC) ) 2020-10-23 13:08 UTC
+	// on WSL:
+	//  Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com) (gcc version 5.4.0 (GCC) ) #488-Microsoft Mon Sep 01 13:43:00 PST 2020
+	b, err := fs.ReadFile(fsys, "proc/version")
+	if err != nil {
+		return false, ""
+	}
+
+	contents := strings.TrimSpace(string(b))
+	return strings.Contains(contents, "Microsoft"), contents
+}
+
+// terminfo dirs for find the terminfo file. refer terminfo.Load()
+var terminfoDirs = []string{"/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo"}
+
+// load terminfo file from the detector fs. the search order is same as the terminfo.Load()
+func (d *detector) loadTerminfo(name string) (*terminfo.Terminfo, error) {
+	var dirs []string
+	if dir, _ := d.lookupEnv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home, _ := d.lookupEnv("HOME"); home != "" {
+		dirs = append(dirs, path.Join(home, ".terminfo"))
+	}
+	if val, _ := d.lookupEnv("TERMINFO_DIRS"); val != "" {
+		dirs = append(dirs, strings.Split(val, ":")...)
+	}
+	dirs = append(dirs, terminfoDirs...)
+
+	for _, dir := range dirs {
+		dir = strings.TrimPrefix(dir, "/")
+		for _, file := range []string{
+			path.Join(dir, name[0:1], name),
+			path.Join(dir, strconv.FormatUint(uint64(name[0]), 16), name),
+		} {
+			buf, err := fs.ReadFile(d.fsys, file)
+			if err != nil {
+				continue
 			}
 
-			wslContents = string(b)
-			return strings.Contains(wslContents, "Microsoft")
+			ti, err := terminfo.Decode(buf)
+			if err != nil {
+				return nil, err
+			}
+			ti.File = "/" + file
+			return ti, nil
 		}
 	}
-	return false
+	return nil, terminfo.ErrDatabaseDirectoryNotFound
 }
 
 /*
@@ -234,6 +576,32 @@ func IsConsole(w io.Writer) bool {
 	return fd == uintptr(syscall.Stdout) || fd == uintptr(syscall.Stdin) || fd == uintptr(syscall.Stderr)
 }
 
+// IsTerminalWriter check the writer is a terminal. eg: os.Stdout on a terminal.
+//
+// Returns false for non file writers like bytes.Buffer, and the files
+// that not a terminal, like pipe and regular file.
+func IsTerminalWriter(w io.Writer) bool {
+	if f, ok := w.(interface{ Fd() uintptr }); ok {
+		return IsTerminal(f.Fd())
+	}
+	return false
+}
+
+// DetectWriterLevel detect the color level supported by the writer.
+//
+// If the writer is a terminal, returns the color level of current terminal.
+// Otherwise, returns LevelNo. eg: bytes.Buffer, pipe, regular file.
+//
+// Usage:
+//
+//	level := color.DetectWriterLevel(os.Stdout)
+func DetectWriterLevel(w io.Writer) Level {
+	if IsTerminalWriter(w) {
+		return TermColorLevel()
+	}
+	return LevelNo
+}
+
 // IsMSys msys(MINGW64) environment, does not necessarily support color
 func IsMSys() bool { /* like "MSYSTEM=MINGW64" */ return len(os.Getenv("MSYSTEM")) > 0 }
 
@@ -264,6 +632,13 @@ func IsSupport256Color() bool {
 	return level > Level16
 }
 
+// IsSupportLink check current terminal is support OSC 8 hyperlinks. see Link()
+//
+// NOTICE: The method will detect terminal info each times,
+//
+//	if only want to get current setting, please direct call SupportLink()
+func IsSupportLink() bool { return detectLinkSupport() }
+
 // IsSupportRGBColor check. alias of the IsSupportTrueColor()
 //
 // NOTICE: The method will detect terminal info each times,
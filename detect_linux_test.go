@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package color
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/gookit/assert"
+	"golang.org/x/sys/unix"
+)
+
+// open a pseudo-terminal pair. returns the master and slave file.
+func openPty(t *testing.T) (ptm, pts *os.File) {
+	ptm, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("open /dev/ptmx failed: %v", err)
+	}
+
+	fd := int(ptm.Fd())
+	if err = unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		_ = ptm.Close()
+		t.Skipf("unlock pty failed: %v", err)
+	}
+
+	num, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = ptm.Close()
+		t.Skipf("get pty number failed: %v", err)
+	}
+
+	pts, err = os.OpenFile("/dev/pts/"+strconv.Itoa(num), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptm.Close()
+		t.Skipf("open pty slave failed: %v", err)
+	}
+	return ptm, pts
+}
+
+func TestIsTerminal_pty(t *testing.T) {
+	is := assert.New(t)
+	ptm, pts := openPty(t)
+	defer ptm.Close()
+	defer pts.Close()
+
+	is.True(IsTerminal(pts.Fd()))
+	is.True(IsTerminalWriter(pts))
+
+	// pipe and regular file
+	r, w, err := os.Pipe()
+	is.NoErr(err)
+	defer r.Close()
+	defer w.Close()
+	is.False(IsTerminal(w.Fd()))
+
+	f, err := os.CreateTemp(t.TempDir(), "color-test")
+	is.NoErr(err)
+	defer f.Close()
+	is.False(IsTerminal(f.Fd()))
+	is.False(IsTerminalWriter(f))
+}
+
+func TestDetectColorLevel_stdout(t *testing.T) {
+	is := assert.New(t)
+	ptm, pts := openPty(t)
+	defer ptm.Close()
+	defer pts.Close()
+
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	mockOsEnvByText("TERM=xterm-256color", func() {
+		// stdout is a terminal
+		os.Stdout = pts
+		isStdoutTerminal = func() bool { return IsTerminal(os.Stdout.Fd()) }
+		is.Eq(Level256, DetectColorLevel())
+
+		// stdout is redirected to a file
+		f, err := os.CreateTemp(t.TempDir(), "color-test")
+		is.NoErr(err)
+		defer f.Close()
+		os.Stdout = f
+		is.Eq(LevelNo, DetectColorLevel())
+
+		// force color output
+		is.NoErr(os.Setenv("FORCE_COLOR", "on"))
+		is.Eq(Level256, DetectColorLevel())
+	})
+}
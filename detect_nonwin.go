@@ -8,12 +8,12 @@ package color
 
 import (
 	"strings"
-	"syscall"
 )
 
 // detect special term color support
-func detectSpecialTermColor(termVal string) (Level, bool) {
+func detectSpecialTermColor(d *detector, termVal string) (Level, bool) {
 	if termVal == "" {
+		d.decide("TERM", "TERM is empty, not support color")
 		return LevelNo, false
 	}
 
@@ -22,27 +22,22 @@ func detectSpecialTermColor(termVal string) (Level, bool) {
 	// on TERM=screen:
 	// - support 256, not support true-color. test on macOS
 	if termVal == "screen" {
+		d.decide("TERM", "TERM=screen support 256 colors")
 		return Level256, false
 	}
 
 	if strings.Contains(termVal, "256color") {
+		d.decide("TERM", "TERM contains 256color")
 		return Level256, false
 	}
 
 	if strings.Contains(termVal, "xterm") {
+		d.decide("TERM", "TERM contains xterm, use 256 colors")
 		return Level256, false
 		// return terminfo.ColorLevelBasic, false
 	}
 
 	// return LevelNo, nil
+	d.decide("TERM", "fallback to basic color")
 	return Level16, false
 }
-
-// IsTerminal returns true if the given file descriptor is a terminal.
-//
-// Usage:
-//
-//	IsTerminal(os.Stdout.Fd())
-func IsTerminal(fd uintptr) bool {
-	return fd == uintptr(syscall.Stdout) || fd == uintptr(syscall.Stdin) || fd == uintptr(syscall.Stderr)
-}
@@ -0,0 +1,11 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !zos && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!zos,!windows
+
+package color
+
+// IsTerminal returns true if the given file descriptor is a terminal.
+//
+// Not supported on the current platform(eg: js, wasip1, plan9), always returns false.
+func IsTerminal(fd uintptr) bool {
+	return false
+}
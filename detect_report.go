@@ -0,0 +1,110 @@
+package color
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// DetectionSignal an inspected signal on detect color level. eg: env TERM, stdout is a terminal
+type DetectionSignal struct {
+	// Name of the signal. eg: env name "TERM", SourceTTY, "terminfo"
+	Name string
+	// Value of the signal
+	Value string
+	// Set is false on the env is not set
+	Set bool
+	// Rule that fired by the signal. empty if the signal is not decided the level
+	Rule string
+}
+
+// DetectionReport explain how the color level was chosen for current env.
+//
+// Usage:
+//
+//	fmt.Println(color.DetectReport())
+type DetectionReport struct {
+	// OS name. eg: linux, darwin, windows
+	OS string
+	// Signals inspected signals, in order of inspected
+	Signals []DetectionSignal
+	// Source the signal name that decided the level
+	Source string
+	// Rule that decided the level
+	Rule string
+	// Level the final color level
+	Level Level
+	// NeedVTP need enable virtual terminal processing, only for Windows
+	NeedVTP bool
+	// Link the terminal supports OSC 8 hyperlinks
+	Link bool
+	// Errors on detect
+	Errors []error
+}
+
+// DetectReport detect the color level for current env, and returns
+// a report explaining how the level was chosen.
+//
+// NOTICE: The method will detect terminal info each times.
+func DetectReport() *DetectionReport {
+	return DetectReportWith(DetectOptions{})
+}
+
+// DetectReportWith detect the color level by the options, and returns the report.
+func DetectReportWith(opts DetectOptions) *DetectionReport {
+	d := newDetectorWith(opts)
+	level, needVTP := d.detect()
+	link := d.detectLink()
+
+	return &DetectionReport{
+		OS:      runtime.GOOS,
+		Signals: d.signals,
+		Source:  d.source,
+		Rule:    d.rule,
+		Level:   level,
+		NeedVTP: needVTP,
+		Link:    link,
+		Errors:  d.errs,
+	}
+}
+
+// Signal get inspected signal by name
+func (r *DetectionReport) Signal(name string) (DetectionSignal, bool) {
+	for _, s := range r.Signals {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return DetectionSignal{}, false
+}
+
+// String format the report as plain text, can be pasted to an issue.
+func (r *DetectionReport) String() string {
+	var sb strings.Builder
+	sb.WriteString("OS: " + r.OS + "\n")
+	sb.WriteString(fmt.Sprintf("Level: %s (need VTP: %v)\n", r.Level.String(), r.NeedVTP))
+	sb.WriteString("Decided by: " + r.Source + " - " + r.Rule + "\n")
+	sb.WriteString(fmt.Sprintf("Hyperlink: %v\n", r.Link))
+
+	sb.WriteString("Signals:\n")
+	for _, s := range r.Signals {
+		val := "<unset>"
+		if s.Set {
+			val = fmt.Sprintf("%q", s.Value)
+		}
+
+		sb.WriteString(fmt.Sprintf("  %-22s %s", s.Name, val))
+		if s.Rule != "" {
+			sb.WriteString(" <- " + s.Rule)
+		}
+		sb.WriteByte('\n')
+	}
+
+	if len(r.Errors) > 0 {
+		sb.WriteString("Errors:\n")
+		for _, err := range r.Errors {
+			sb.WriteString("  - " + err.Error() + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -1,9 +1,15 @@
 package color
 
 import (
+	"bytes"
+	"io/fs"
+	"os"
+	"runtime"
 	"testing"
+	"testing/fstest"
 
 	"github.com/gookit/assert"
+	"github.com/xo/terminfo"
 )
 
 func TestDetectColorLevel(t *testing.T) {
@@ -319,3 +325,221 @@ TERM_PROGRAM_VERSION=4.5
 		is.Eq(Level256, lv)
 	})
 }
+
+func TestDetectWriterLevel(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq(LevelNo, DetectWriterLevel(&bytes.Buffer{}))
+	is.False(IsTerminalWriter(&bytes.Buffer{}))
+
+	// regular file
+	f, err := os.CreateTemp(t.TempDir(), "color-test")
+	is.NoErr(err)
+	defer f.Close()
+	is.False(IsTerminalWriter(f))
+	is.Eq(LevelNo, DetectWriterLevel(f))
+
+	if IsTerminalWriter(os.Stdout) {
+		is.Eq(TermColorLevel(), DetectWriterLevel(os.Stdout))
+	} else {
+		is.Eq(LevelNo, DetectWriterLevel(os.Stdout))
+	}
+}
+
+func TestDetectColorLevelSource(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		env    string
+		level  Level
+		source string
+	}{
+		{"NO_COLOR=1\nFORCE_COLOR=3", LevelNo, EnvNoColor},
+		{"FORCE_COLOR=0\nTERM=xterm-256color", LevelNo, EnvForceColor},
+		{"FORCE_COLOR=false\nCOLORTERM=truecolor", LevelNo, EnvForceColor},
+		{"FORCE_COLOR=1\nCOLORTERM=truecolor", Level16, EnvForceColor},
+		{"FORCE_COLOR=2", Level256, EnvForceColor},
+		{"FORCE_COLOR=3\nCLICOLOR=0", LevelRgb, EnvForceColor},
+		{"FORCE_COLOR=on", Level16, EnvForceColor},
+		{"FORCE_COLOR=on\nCLICOLOR=0\nCOLORTERM=truecolor", LevelRgb, "COLORTERM"},
+		{"CLICOLOR=0\nTERM=xterm-256color", LevelNo, EnvCliColor},
+		{"CLICOLOR=1\nTERM=xterm-256color", Level256, "TERM"},
+		{"CLICOLOR_FORCE=1\nCLICOLOR=0", Level16, EnvCliColorForce},
+		{"CLICOLOR_FORCE=0\nCLICOLOR=0", LevelNo, EnvCliColor},
+		{"TERM_PROGRAM=Apple_Terminal", Level256, "TERM_PROGRAM"},
+		{"TERMINAL_EMULATOR=JetBrains-JediTerm", LevelRgb, "TERMINAL_EMULATOR"},
+	}
+
+	for _, tt := range tests {
+		mockOsEnvByText(tt.env, func() {
+			level, source := DetectColorLevelSource()
+			is.Eq(tt.level, level, tt.env)
+			is.Eq(tt.source, source, tt.env)
+		})
+	}
+
+	// stdout is not a terminal
+	mockOsEnvByText("TERM=xterm-256color", func() {
+		isStdoutTerminal = func() bool { return false }
+
+		level, source := DetectColorLevelSource()
+		is.Eq(LevelNo, level)
+		is.Eq(SourceTTY, source)
+
+		// force color output
+		is.NoErr(os.Setenv("CLICOLOR_FORCE", "1"))
+		level, source = DetectColorLevelSource()
+		is.Eq(Level256, level)
+		is.Eq("TERM", source)
+	})
+}
+
+func TestDetectReport(t *testing.T) {
+	is := assert.New(t)
+
+	mockOsEnvByText(`
+TERM=xterm-256color
+COLORTERM=truecolor
+`, func() {
+		r := DetectReport()
+		is.Eq(LevelRgb, r.Level)
+		is.Eq("COLORTERM", r.Source)
+		is.Eq("COLORTERM contains truecolor or 24bit", r.Rule)
+		is.Eq(runtime.GOOS, r.OS)
+
+		s, ok := r.Signal("COLORTERM")
+		is.True(ok)
+		is.True(s.Set)
+		is.Eq("truecolor", s.Value)
+		is.Eq(r.Rule, s.Rule)
+
+		s, ok = r.Signal(EnvNoColor)
+		is.True(ok)
+		is.False(s.Set)
+		is.Eq("", s.Rule)
+
+		_, ok = r.Signal("not-exist")
+		is.False(ok)
+
+		str := r.String()
+		is.Contains(str, "Level: millions")
+		is.Contains(str, "Decided by: COLORTERM - COLORTERM contains truecolor or 24bit")
+		is.Contains(str, `"truecolor" <- COLORTERM contains truecolor or 24bit`)
+		is.Contains(str, "<unset>")
+	})
+
+	mockOsEnvByText("TERM=xterm-256color", func() {
+		isStdoutTerminal = func() bool { return false }
+
+		r := DetectReport()
+		is.Eq(LevelNo, r.Level)
+		is.Eq(SourceTTY, r.Source)
+
+		s, ok := r.Signal(SourceTTY)
+		is.True(ok)
+		is.Eq("false", s.Value)
+		is.Eq("stdout is not a terminal, disable color", s.Rule)
+		// not inspect the TERM
+		_, ok = r.Signal("TERM")
+		is.False(ok)
+	})
+
+	mockOsEnvByText("TERM=not-exist-term", func() {
+		r := DetectReport()
+		is.Eq(Level16, r.Level)
+		is.Eq("TERM", r.Source)
+		is.NotEmpty(r.Errors)
+		is.Contains(r.String(), "Errors:")
+	})
+}
+
+// make a minimal terminfo file contents with the max_colors capability
+func makeTerminfo(name string, maxColors int) []byte {
+	name += "\x00"
+	nums := make([]int, terminfo.MaxColors+1)
+	for i := range nums {
+		nums[i] = 0xFFFF // absent
+	}
+	nums[terminfo.MaxColors] = maxColors
+
+	// header: magic, names size, bools count, nums count, strings count, string table size
+	ints := []int{0o432, len(name), 0, len(nums), 0, 0}
+	buf := make([]byte, 0, 12+len(name)+1+2*len(nums))
+	for _, v := range ints {
+		buf = append(buf, byte(v), byte(v>>8))
+	}
+
+	buf = append(buf, name...)
+	if len(buf)%2 == 1 {
+		buf = append(buf, 0)
+	}
+	for _, v := range nums {
+		buf = append(buf, byte(v), byte(v>>8))
+	}
+	return buf
+}
+
+func TestDetectColorLevelWith(t *testing.T) {
+	rootFS := fstest.MapFS{
+		"usr/share/terminfo/x/xterm-256color": {Data: makeTerminfo("xterm-256color", 256)},
+		"usr/share/terminfo/l/linux16":        {Data: makeTerminfo("linux16", 16)},
+		"home/inhere/.terminfo/m/my-term":     {Data: makeTerminfo("my-term", 88)},
+	}
+	wslFS := fstest.MapFS{
+		"proc/version": {Data: []byte("Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com) #488-Microsoft")},
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		fsys   fs.FS
+		level  Level
+		source string
+	}{
+		{"iTerm 3", map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.4.19"}, rootFS, LevelRgb, "TERM_PROGRAM_VERSION"},
+		{"iTerm 2", map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "2.1"}, rootFS, Level256, "TERM_PROGRAM"},
+		{"Apple_Terminal", map[string]string{"TERM_PROGRAM": "Apple_Terminal", "TERM": "xterm-256color"}, rootFS, Level256, "TERM_PROGRAM"},
+		{"screen", map[string]string{"TERM": "screen", "COLORTERM": "truecolor"}, rootFS, Level256, "TERM"},
+		{"tmux", map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default,1,0"}, rootFS, Level256, "TERM"},
+		{"JetBrains", map[string]string{"TERMINAL_EMULATOR": "JetBrains-JediTerm", "TERM": "xterm-256color"}, rootFS, LevelRgb, "TERMINAL_EMULATOR"},
+		{"WSL", map[string]string{"WSL_DISTRO_NAME": "Debian", "TERM": "xterm-256color"}, wslFS, LevelRgb, "WSL_DISTRO_NAME"},
+		{"not WSL", map[string]string{"WSL_DISTRO_NAME": "Debian", "TERM": "xterm-256color"}, rootFS, Level256, "TERM"},
+		{"terminfo 256", map[string]string{"TERM": "xterm-256color"}, rootFS, Level256, "TERM"},
+		{"terminfo 16", map[string]string{"TERM": "linux16"}, rootFS, Level16, "TERM"},
+		{"terminfo HOME", map[string]string{"TERM": "my-term", "HOME": "/home/inhere"}, rootFS, Level16, "TERM"},
+		{"no TERM", map[string]string{}, rootFS, LevelNo, "TERM"},
+		{"NO_COLOR", map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, rootFS, LevelNo, EnvNoColor},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			opts := DetectOptions{
+				LookupEnv: func(key string) (string, bool) {
+					val, ok := tt.env[key]
+					return val, ok
+				},
+				FS:         tt.fsys,
+				IsTerminal: func() bool { return true },
+			}
+			is.Eq(tt.level, DetectColorLevelWith(opts))
+
+			r := DetectReportWith(opts)
+			is.Eq(tt.level, r.Level)
+			is.Eq(tt.source, r.Source)
+		})
+	}
+
+	t.Run("not terminal", func(t *testing.T) {
+		t.Parallel()
+		opts := DetectOptions{
+			LookupEnv:  func(key string) (string, bool) { return "", false },
+			FS:         rootFS,
+			IsTerminal: func() bool { return false },
+		}
+		assert.Eq(t, LevelNo, DetectColorLevelWith(opts))
+	})
+}
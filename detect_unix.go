@@ -0,0 +1,16 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package color
+
+import "golang.org/x/sys/unix"
+
+// IsTerminal returns true if the given file descriptor is a terminal.
+//
+// Usage:
+//
+//	IsTerminal(os.Stdout.Fd())
+func IsTerminal(fd uintptr) bool {
+	_, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+	return err == nil
+}
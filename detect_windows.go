@@ -11,7 +11,7 @@ package color
 //	golang.org/x/crypto/ssh/terminal
 //	https://docs.microsoft.com/en-us/windows/console
 import (
-	"os"
+	"fmt"
 	"syscall"
 	"unsafe"
 
@@ -111,39 +111,45 @@ var winVersion, _, buildNumber = windows.RtlGetNtVersionNumbers()
 //	https://github.com/gookit/color/issues/25#issuecomment-738727917
 //
 // detects the color level supported on Windows: cmd, powerShell
-func detectSpecialTermColor(termVal string) (tl Level, needVTP bool) {
-	if os.Getenv("ConEmuANSI") == "ON" {
-		debugf("support True Color by ConEmuANSI=ON")
+func detectSpecialTermColor(d *detector, termVal string) (tl Level, needVTP bool) {
+	if d.getenv("ConEmuANSI") == "ON" {
 		// ConEmuANSI is "ON" for generic ANSI support
 		// but True Color option is enabled by default
 		// I am just assuming that people wouldn't have disabled it
 		// Even if it is not enabled then ConEmu will auto round off
 		// accordingly
+		d.decide("ConEmuANSI", "support True Color by ConEmuANSI=ON")
 		return LevelRgb, false
 	}
 
+	d.addSignal("windows version", fmt.Sprintf("%d build %d", winVersion, buildNumber))
+
 	// Before Windows 10 Build Number 10586, console never supported ANSI Colors
 	if buildNumber < 10586 || winVersion < 10 {
 		// Detect if using ANSICON on older systems
-		if os.Getenv("ANSICON") != "" {
-			conVersion := os.Getenv("ANSICON_VER")
+		if d.getenv("ANSICON") != "" {
+			conVersion := d.getenv("ANSICON_VER")
 			// 8-bit Colors were only supported after v1.81 release
 			if conVersion >= "181" {
+				d.decide("ANSICON_VER", "ANSICON >= v1.81 support 256 colors")
 				return Level256, false
 			}
+			d.decide("ANSICON_VER", "ANSICON < v1.81 support basic color")
 			return Level16, false
 		}
 
+		d.decide("windows version", "console not support ANSI colors before Windows 10 build 10586")
 		return LevelNo, false
 	}
 
 	// True Color is not available before build 14931 so fallback to 8-bit color.
 	if buildNumber < 14931 {
+		d.decide("windows version", "support 256 colors before Windows 10 build 14931, needVTP=true")
 		return Level256, true
 	}
 
 	// Windows 10 build 14931 is the first release that supports 16m/TrueColor
-	debugf("support True Color on windows version >= 14931, needVTP=true")
+	d.decide("windows version", "support True Color on windows version >= 14931, needVTP=true")
 	return LevelRgb, true
 }
 
@@ -0,0 +1,57 @@
+package color
+
+import "strings"
+
+// the OSC 8 sequence to open a hyperlink, format: linkOpenSeq + URL + ST
+const linkOpenSeq = "\x1b]8;;"
+
+// Link render a clickable hyperlink by OSC 8. text is empty will use the url as text.
+//
+// If the terminal does not support hyperlinks or the color render is disabled,
+// will degrade to "text (url)". see SupportLink(), SetSupportLink()
+//
+// Usage:
+//
+//	fmt.Println("see", color.Link("https://github.com/gookit/color", "docs"))
+//	// in color tags
+//	color.Println("see <link=https://github.com/gookit/color>docs</>")
+func Link(url, text string) string { return std.Link(url, text) }
+
+// Link render a clickable hyperlink by OSC 8. see Link()
+func (r *Renderer) Link(url, text string) string {
+	return r.renderLink(url, text, true)
+}
+
+// render the hyperlink. render is false will output the plain text "text (url)".
+func (r *Renderer) renderLink(url, text string, render bool) string {
+	url = strings.Map(dropCtrlChar, url)
+	if text == "" {
+		text = url
+	}
+
+	if !render || !*r.enable || !r.link || url == "" {
+		if text == url || url == "" {
+			return text
+		}
+		return text + " (" + url + ")"
+	}
+	return linkOpenSeq + url + "\x1b\\" + text + linkCloseSeq
+}
+
+// drop the control chars in the URL, they can break the OSC sequence.
+func dropCtrlChar(r rune) rune {
+	if r < 0x20 || r == 0x7f {
+		return -1
+	}
+	return r
+}
+
+// get the URL of the link tag. eg: "link=https://github.com" -> "https://github.com"
+//
+// returns empty if the tag is not a link tag.
+func linkTagURL(tag string) string {
+	if strings.HasPrefix(tag, "link=") {
+		return strings.TrimSpace(tag[5:])
+	}
+	return ""
+}
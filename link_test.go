@@ -0,0 +1,114 @@
+package color
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestLink(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	old := SetSupportLink(true)
+	defer SetSupportLink(old)
+	is.True(SupportLink())
+
+	is.Eq("\x1b]8;;https://github.com\x1b\\home\x1b]8;;\x1b\\", Link("https://github.com", "home"))
+	is.Eq("\x1b]8;;https://github.com\x1b\\https://github.com\x1b]8;;\x1b\\", Link("https://github.com", ""))
+	// control chars are dropped
+	is.Eq("\x1b]8;;https://a.b/x\x1b\\x\x1b]8;;\x1b\\", Link("https://a.b/\x1bx\a", "x"))
+	is.Eq("home", Link("", "home"))
+	is.Eq("home", ClearCode(Link("https://github.com", "home")))
+
+	// not supported
+	SetSupportLink(false)
+	is.Eq("home (https://github.com)", Link("https://github.com", "home"))
+	is.Eq("https://github.com", Link("https://github.com", ""))
+
+	// color disabled
+	SetSupportLink(true)
+	Disable()
+	is.Eq("home (https://github.com)", Link("https://github.com", "home"))
+	Enable = true
+}
+
+func TestTagParser_link(t *testing.T) {
+	is := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	r := NewRenderer(buf)
+	r.SetLevel(Level16)
+	r.SetSupportLink(true)
+	is.True(r.SupportLink())
+
+	p := r.TagParser()
+	is.Eq("see \x1b]8;;https://github.com/gookit/color?a=b;c#d\x1b\\docs\x1b]8;;\x1b\\.",
+		p.Parse("see <link=https://github.com/gookit/color?a=b;c#d>docs</>."))
+	// nested color tags
+	is.Eq("\x1b[0;32mok \x1b[0m\x1b[0;32m\x1b]8;;https://a.b\x1b\\\x1b[1mdocs\x1b[0m\x1b[0;32m\x1b]8;;\x1b\\\x1b[0m",
+		p.Parse("<info>ok <link=https://a.b><op=bold>docs</></></>"))
+	// not a link tag
+	is.Eq("<link>a</>", p.Parse("<link>a</>"))
+	is.NoErr(p.Validate("<link=https://a.b>docs</link=https://a.b>"))
+	is.True(errors.Is(p.Validate("<link>a</>").(TagErrors)[0], ErrUnknownTag))
+
+	// not supported
+	r.SetSupportLink(false)
+	is.Eq("see docs (https://a.b).", p.Parse("see <link=https://a.b>docs</>."))
+
+	// color is disabled or not render to terminal, also output plain text
+	r.SetSupportLink(true)
+	r.SetLevel(LevelNo)
+	is.Eq("see docs (https://a.b).", r.Sprint("see <link=https://a.b>docs</>."))
+
+	r.SetLevel(Level16)
+	r.SetDetectWriter(true)
+	r.Print("see <link=https://a.b><info>docs</></>.")
+	is.Eq("see docs (https://a.b).", buf.String())
+
+	// template with link tag will parse on execution
+	r.SetDetectWriter(false)
+	tpl, err := r.Compile("<link=https://a.b>%s</>")
+	is.NoErr(err)
+	is.Eq("\x1b]8;;https://a.b\x1b\\docs\x1b]8;;\x1b\\", tpl.Sprintf("docs"))
+}
+
+func TestDetectLinkWith(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		tty  bool
+		want bool
+	}{
+		{"iTerm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true, true},
+		{"vscode", map[string]string{"TERM_PROGRAM": "vscode"}, true, true},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "abc"}, true, true},
+		{"VTE new", map[string]string{"VTE_VERSION": "6003"}, true, true},
+		{"VTE old", map[string]string{"VTE_VERSION": "4601"}, true, false},
+		{"Konsole", map[string]string{"KONSOLE_VERSION": "220401"}, true, true},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true, true},
+		{"Apple_Terminal", map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, true, false},
+		{"not terminal", map[string]string{"TERM_PROGRAM": "iTerm.app"}, false, false},
+		{"force", map[string]string{EnvForceHyperlink: "1"}, false, true},
+		{"force disable", map[string]string{EnvForceHyperlink: "0", "TERM_PROGRAM": "iTerm.app"}, true, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DetectOptions{
+				LookupEnv: func(key string) (string, bool) {
+					val, ok := tt.env[key]
+					return val, ok
+				},
+				IsTerminal: func() bool { return tt.tty },
+			}
+			assert.Eq(t, tt.want, DetectLinkWith(opts))
+			assert.Eq(t, tt.want, DetectReportWith(opts).Link)
+		})
+	}
+}
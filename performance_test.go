@@ -2,7 +2,9 @@ package color
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -62,4 +64,163 @@ func BenchmarkFmtSprint_UserPattern(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = fmt.Sprint(strconv.Itoa(i)+". ", "test")
 	}
-}
\ No newline at end of file
+}
+
+// long log line with many color tags
+var longTagLine = strings.Repeat("<info>[INFO]</> 2024-01-02 <fg=167;op=bold>user</> login from <cyan>127.0.0.1</>, ", 200)
+
+// the regex engine before the tag lexer, use for compare.
+var (
+	benchMatchRegex = regexp.MustCompile(MatchExpr)
+	benchStripRegex = regexp.MustCompile(StripExpr)
+)
+
+func regexParseTag(str string) string {
+	for _, item := range benchMatchRegex.FindAllStringSubmatch(str, -1) {
+		full, tag, body := item[0], item[1], item[2]
+		var code string
+		if strings.ContainsRune(tag, '=') {
+			code = ParseCodeFromAttr(tag)
+		} else {
+			code = colorTags[tag]
+		}
+		if code != "" {
+			str = strings.Replace(str, full, RenderString(code, body), 1)
+		}
+	}
+	return str
+}
+
+func BenchmarkTagParser_Parse_LongLine(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = tagParser.Parse(longTagLine)
+	}
+}
+
+func BenchmarkTagParser_Parse_LongLine_Regex(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = regexParseTag(longTagLine)
+	}
+}
+
+func BenchmarkClearTag_LongLine(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ClearTag(longTagLine)
+	}
+}
+
+func BenchmarkClearTag_LongLine_Regex(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = benchStripRegex.ReplaceAllString(longTagLine, "")
+	}
+}
+
+func BenchmarkSprintf_Tag(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Sprintf("<info>%s</> done in <cyan>%dms</>", "build", i)
+	}
+}
+
+func BenchmarkTemplate_Sprintf(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	tpl := MustCompile("<info>%s</> done in <cyan>%dms</>")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = tpl.Sprintf("build", i)
+	}
+}
+
+func BenchmarkColor_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = FgGreen.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkColor256_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	c := C256(132)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = c.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkRGBColor_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	c := RGB(204, 123, 56)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = c.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkStyle_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	s := Style{FgGreen, BgBlack, OpBold}
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = s.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkStyle256_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	s := S256(192, 38).AddOpts(OpBold)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = s.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkRGBStyle_AppendRender(b *testing.B) {
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	s := NewRGBStyle(RGB(20, 144, 234), RGB(234, 78, 23)).AddOpts(OpBold)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = s.AppendRender(buf[:0], "Hello World")
+	}
+}
+
+func BenchmarkRGBColor_String(b *testing.B) {
+	c := RGB(204, 123, 56)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = c.String()
+	}
+}
@@ -0,0 +1,197 @@
+package render
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/gookit/color/ansi"
+)
+
+// HTMLOptions for the HTML()
+type HTMLOptions struct {
+	// Theme for resolve the colors, default is DefaultTheme
+	Theme *Theme
+	// Classes use CSS classes instead of inline styles. see HTMLStyleSheet()
+	//
+	// NOTE: the 256(>15) and RGB colors are always output as inline styles.
+	Classes bool
+	// ClassPrefix of the CSS classes, default is "ansi-"
+	ClassPrefix string
+	// Pre wrap the output by the <pre> tag, with the theme fg and bg colors.
+	Pre bool
+}
+
+func (o *HTMLOptions) init() {
+	if o.Theme == nil {
+		o.Theme = DefaultTheme
+	}
+	if o.ClassPrefix == "" {
+		o.ClassPrefix = "ansi-"
+	}
+}
+
+// HTML convert the ANSI colored text to HTML spans.
+//
+// Usage:
+//
+//	s := render.HTML("\x1b[1;31merror\x1b[0m: not found", render.HTMLOptions{})
+//	// <span style="color:#ff0000;font-weight:bold">error</span>: not found
+func HTML(s string, opts HTMLOptions) string {
+	opts.init()
+
+	var sb strings.Builder
+	sb.Grow(len(s) * 2)
+	if opts.Pre {
+		if opts.Classes {
+			sb.WriteString(`<pre class="` + opts.ClassPrefix + `pre">`)
+		} else {
+			sb.WriteString(`<pre style="color:#` + opts.Theme.Foreground.Hex() + `;background-color:#` + opts.Theme.Background.Hex() + `">`)
+		}
+	}
+
+	for i, line := range Parse(s) {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+
+		for _, sp := range line {
+			text := html.EscapeString(sp.Text)
+			classes, styles := htmlStyle(sp.Style, &opts)
+			if classes == "" && styles == "" {
+				sb.WriteString(text)
+				continue
+			}
+
+			sb.WriteString("<span")
+			if classes != "" {
+				sb.WriteString(` class="` + classes + `"`)
+			}
+			if styles != "" {
+				sb.WriteString(` style="` + styles + `"`)
+			}
+			sb.WriteString(">" + text + "</span>")
+		}
+	}
+
+	if opts.Pre {
+		sb.WriteString("</pre>")
+	}
+	return sb.String()
+}
+
+// convert the style to CSS classes and inline styles
+func htmlStyle(s Style, opts *HTMLOptions) (classes, styles string) {
+	if s.IsZero() {
+		return
+	}
+
+	var cs, ss []string
+	t, pfx := opts.Theme, opts.ClassPrefix
+
+	// colors, the default colors need to be set if reverse.
+	fg, bg := s.Fg, s.Bg
+	fgDef, bgDef := t.Foreground, t.Background
+	if s.Reverse {
+		fg, bg = bg, fg
+		fgDef, bgDef = bgDef, fgDef
+	}
+
+	addColor := func(c ansi.Color, def color.RGBColor, name, prop string) {
+		if c.Type == ansi.ColorNone && !s.Reverse {
+			return
+		}
+
+		if idx, ok := paletteIndex(c); ok && opts.Classes {
+			cs = append(cs, pfx+name+"-"+strconv.Itoa(idx))
+		} else {
+			ss = append(ss, prop+":#"+t.Color(c, def).Hex())
+		}
+	}
+	addColor(fg, fgDef, "fg", "color")
+	addColor(bg, bgDef, "bg", "background-color")
+
+	// text attributes
+	var decors []string
+	for _, attr := range []struct {
+		on         bool
+		name, prop string
+	}{
+		{s.Bold, "bold", "font-weight:bold"},
+		{s.Dim, "dim", "opacity:0.5"},
+		{s.Italic, "italic", "font-style:italic"},
+		{s.Underline, "underline", ""},
+		{s.Strike, "strike", ""},
+		{s.Blink, "blink", ""},
+		{s.Hidden, "hidden", "visibility:hidden"},
+	} {
+		if !attr.on {
+			continue
+		}
+
+		if opts.Classes {
+			cs = append(cs, pfx+attr.name)
+		} else if attr.prop == "" {
+			decors = append(decors, htmlDecors[attr.name])
+		} else {
+			ss = append(ss, attr.prop)
+		}
+	}
+
+	if len(decors) > 0 {
+		ss = append(ss, "text-decoration:"+strings.Join(decors, " "))
+	}
+	return strings.Join(cs, " "), strings.Join(ss, ";")
+}
+
+// the text-decoration values
+var htmlDecors = map[string]string{
+	"underline": "underline",
+	"strike":    "line-through",
+	"blink":     "blink",
+}
+
+// get the palette index of the basic color, or the 256 color < 16
+func paletteIndex(c ansi.Color) (int, bool) {
+	switch c.Type {
+	case ansi.ColorBasic:
+		return int(c.Index & 15), true
+	case ansi.Color256:
+		return int(c.Index), c.Index < 16
+	}
+	return 0, false
+}
+
+// HTMLStyleSheet generate the CSS for the classes output by HTML(). prefix default is "ansi-"
+//
+// Usage:
+//
+//	css := render.HTMLStyleSheet(render.DefaultTheme, "")
+//	s := render.HTML(text, render.HTMLOptions{Classes: true, Pre: true})
+func HTMLStyleSheet(t *Theme, prefix string) string {
+	if t == nil {
+		t = DefaultTheme
+	}
+	if prefix == "" {
+		prefix = "ansi-"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("." + prefix + "pre { color: #" + t.Foreground.Hex() + "; background-color: #" + t.Background.Hex() + "; }\n")
+	for i, c := range t.Palette {
+		idx := strconv.Itoa(i)
+		sb.WriteString("." + prefix + "fg-" + idx + " { color: #" + c.Hex() + "; }\n")
+		sb.WriteString("." + prefix + "bg-" + idx + " { background-color: #" + c.Hex() + "; }\n")
+	}
+
+	sb.WriteString("." + prefix + "bold { font-weight: bold; }\n")
+	sb.WriteString("." + prefix + "dim { opacity: 0.5; }\n")
+	sb.WriteString("." + prefix + "italic { font-style: italic; }\n")
+	sb.WriteString("." + prefix + "underline { text-decoration: underline; }\n")
+	sb.WriteString("." + prefix + "strike { text-decoration: line-through; }\n")
+	sb.WriteString("." + prefix + "underline." + prefix + "strike { text-decoration: underline line-through; }\n")
+	sb.WriteString("." + prefix + "blink { text-decoration: blink; }\n")
+	sb.WriteString("." + prefix + "hidden { visibility: hidden; }\n")
+	return sb.String()
+}
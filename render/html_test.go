@@ -0,0 +1,52 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/gookit/assert"
+	"github.com/gookit/color"
+	"github.com/gookit/color/render"
+)
+
+func TestHTML(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("a &lt;b&gt; &amp; c", render.HTML("a <b> & c", render.HTMLOptions{}))
+
+	s := render.HTML("\x1b[1;91merror\x1b[0m: not found\n\x1b[38;2;1;2;3;48;5;208;3;4;9mx", render.HTMLOptions{})
+	is.Eq(`<span style="color:#ff0000;font-weight:bold">error</span>: not found`+"\n"+
+		`<span style="color:#010203;background-color:#ff8700;font-style:italic;text-decoration:underline line-through">x</span>`, s)
+
+	// xterm 256 colors
+	s = render.HTML("\x1b[38;5;196ma\x1b[38;5;16;48;5;231mb", render.HTMLOptions{})
+	is.Eq(`<span style="color:#ff0000">a</span><span style="color:#000000;background-color:#ffffff">b</span>`, s)
+
+	// reverse with default colors
+	s = render.HTML("\x1b[7mx", render.HTMLOptions{})
+	is.Eq(`<span style="color:#000000;background-color:#e5e5e5">x</span>`, s)
+
+	// use the rendered text by color package
+	color.ForceOpenColor()
+	s = render.HTML(color.RGB(30, 144, 255).Sprint("msg"), render.HTMLOptions{Pre: true, Theme: render.LightTheme})
+	is.Eq(`<pre style="color:#000000;background-color:#ffffff"><span style="color:#1e90ff">msg</span></pre>`, s)
+}
+
+func TestHTML_classes(t *testing.T) {
+	is := assert.New(t)
+
+	opts := render.HTMLOptions{Classes: true}
+	s := render.HTML("\x1b[1;31;42mab\x1b[0m \x1b[38;5;208;2mc\x1b[0m \x1b[7md", opts)
+	is.Eq(`<span class="ansi-fg-1 ansi-bg-2 ansi-bold">ab</span> `+
+		`<span class="ansi-dim" style="color:#ff8700">c</span> `+
+		`<span style="color:#000000;background-color:#e5e5e5">d</span>`, s)
+
+	opts.ClassPrefix = "t-"
+	opts.Pre = true
+	is.Eq(`<pre class="t-pre"><span class="t-underline">u</span></pre>`, render.HTML("\x1b[4mu", opts))
+
+	css := render.HTMLStyleSheet(nil, "t-")
+	is.Contains(css, ".t-pre { color: #e5e5e5; background-color: #000000; }\n")
+	is.Contains(css, ".t-fg-9 { color: #ff0000; }\n")
+	is.Contains(css, ".t-bg-1 { background-color: #800000; }\n")
+	is.Contains(css, ".t-bold { font-weight: bold; }\n")
+}
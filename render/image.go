@@ -0,0 +1,188 @@
+package render
+
+import (
+	"image"
+	stdcolor "image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// the cell size of the embedded font in pixels, before scaling.
+// the 8x8 glyph is drawn at the glyphTop row of the cell.
+const (
+	cellW    = 8
+	cellH    = 12
+	glyphTop = 2
+	// the rows of the underline and strikethrough in the cell
+	underRow  = 10
+	strikeRow = 6
+)
+
+// ImageOptions for the Image()
+type ImageOptions struct {
+	// Theme for resolve the colors, default is DefaultTheme
+	Theme *Theme
+	// Scale the pixel size of the font, default is 2. the cell size is 8x12 * Scale
+	Scale int
+	// Padding the space around the text in pixels, default is one cell width.
+	Padding int
+	// Columns the min width by columns, default is the max width of the lines.
+	Columns int
+}
+
+func (o *ImageOptions) init() {
+	if o.Theme == nil {
+		o.Theme = DefaultTheme
+	}
+	if o.Scale <= 0 {
+		o.Scale = 2
+	}
+	if o.Padding <= 0 {
+		o.Padding = cellW * o.Scale
+	}
+}
+
+// Image rasterize the ANSI colored text to an image by the embedded 8x8 bitmap font.
+//
+// Only the printable ASCII chars have glyphs, the others are drawn as a box.
+// The wide chars will take two cells. see color.VisibleWidth()
+//
+// Usage:
+//
+//	img := render.Image(output, render.ImageOptions{Scale: 2})
+//	png.Encode(f, img)
+func Image(s string, opts ImageOptions) *image.RGBA {
+	opts.init()
+	t := opts.Theme
+
+	lines := Parse(strings.TrimSuffix(s, "\n"))
+	cols, maxCol := layoutLines(lines)
+	if opts.Columns > maxCol {
+		maxCol = opts.Columns
+	}
+
+	sc, pad := opts.Scale, opts.Padding
+	width := maxCol*cellW*sc + pad*2
+	height := len(lines)*cellH*sc + pad*2
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(rgba(t.Background)), image.Point{}, draw.Src)
+
+	for i, line := range lines {
+		y := pad + i*cellH*sc
+
+		for j, sp := range line {
+			x := pad + cols[i][j]*cellW*sc
+			fg, bg := sp.Style.Colors(t)
+			if sp.Style.Bg.Type != 0 || sp.Style.Reverse {
+				w := color.VisibleWidth(sp.Text) * cellW * sc
+				draw.Draw(img, image.Rect(x, y, x+w, y+cellH*sc), image.NewUniform(rgba(bg)), image.Point{}, draw.Src)
+			}
+
+			if sp.Style.Hidden {
+				continue
+			}
+			if sp.Style.Dim {
+				fg = blendRGB(fg, bg)
+			}
+
+			gc := &glyphCanvas{img: img, scale: sc, c: rgba(fg)}
+			for _, r := range sp.Text {
+				n := color.VisibleWidth(string(r))
+				if n == 0 {
+					continue
+				}
+
+				if r != ' ' {
+					gc.drawGlyph(x, y, glyphOf(r), n, sp.Style)
+				}
+				if sp.Style.Underline {
+					gc.hline(x, y+underRow*sc, n*cellW)
+				}
+				if sp.Style.Strike {
+					gc.hline(x, y+strikeRow*sc, n*cellW)
+				}
+				x += n * cellW * sc
+			}
+		}
+	}
+	return img
+}
+
+// PNG render the ANSI colored text to a PNG image and write to w. see Image()
+//
+// Usage:
+//
+//	f, _ := os.Create("demo.png")
+//	err := render.PNG(f, output, render.ImageOptions{})
+func PNG(w io.Writer, s string, opts ImageOptions) error {
+	return png.Encode(w, Image(s, opts))
+}
+
+// draw the glyphs to the image by the scale
+type glyphCanvas struct {
+	img   *image.RGBA
+	scale int
+	c     stdcolor.RGBA
+}
+
+// draw the glyph at the cell x, y. the glyph is stretched to the cells n.
+//
+// bold is drawn twice with offset one pixel, italic is shear to the right on the top rows.
+func (gc *glyphCanvas) drawGlyph(x, y int, g *[8]byte, n int, s Style) {
+	for row, bits := range g {
+		var shift int
+		if s.Italic {
+			shift = (7 - row) / 3
+		}
+
+		for col := 0; col < 8; col++ {
+			if bits>>col&1 == 0 {
+				continue
+			}
+
+			for k := 0; k < n; k++ {
+				px := col*n + k + shift
+				gc.dot(x, y, px, glyphTop+row)
+				if s.Bold {
+					gc.dot(x, y, px+1, glyphTop+row)
+				}
+			}
+		}
+	}
+}
+
+// draw a horizontal line with the width in the cell pixels
+func (gc *glyphCanvas) hline(x, y, width int) {
+	for px := 0; px < width; px++ {
+		gc.dot(x, y, px, 0)
+	}
+}
+
+// fill the scaled dot at the cell pixel px, py
+func (gc *glyphCanvas) dot(x, y, px, py int) {
+	x += px * gc.scale
+	y += py * gc.scale
+	for i := 0; i < gc.scale; i++ {
+		for j := 0; j < gc.scale; j++ {
+			gc.img.SetRGBA(x+j, y+i, gc.c)
+		}
+	}
+}
+
+func rgba(c color.RGBColor) stdcolor.RGBA {
+	return stdcolor.RGBA{R: c[0], G: c[1], B: c[2], A: 0xff}
+}
+
+// blend the fg color with bg by half, for render the dim text
+func blendRGB(fg, bg color.RGBColor) color.RGBColor {
+	return color.RGB(
+		uint8((int(fg[0])+int(bg[0]))/2),
+		uint8((int(fg[1])+int(bg[1]))/2),
+		uint8((int(fg[2])+int(bg[2]))/2),
+	)
+}
@@ -0,0 +1,67 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/gookit/assert"
+	"github.com/gookit/color/render"
+)
+
+func TestImage(t *testing.T) {
+	is := assert.New(t)
+
+	img := render.Image("\x1b[41mA\x1b[0m\n\x1b[38;5;208mA\x1b[38;2;1;2;3m 你\n", render.ImageOptions{})
+	// 4 cols and 2 lines, cell 16x24, padding 16
+	is.Eq(4*16+32, img.Bounds().Dx())
+	is.Eq(2*24+32, img.Bounds().Dy())
+
+	// background and padding
+	is.Eq(color.RGBA{A: 0xff}, img.RGBAAt(0, 0))
+	is.Eq(color.RGBA{R: 128, A: 0xff}, img.RGBAAt(16, 16))
+	is.Eq(color.RGBA{A: 0xff}, img.RGBAAt(32, 16))
+
+	// the glyph pixels of "A": row 0 is 0x0C, the top of glyph is row 2
+	is.Eq(color.RGBA{R: 229, G: 229, B: 229, A: 0xff}, img.RGBAAt(16+2*2, 16+2*2))
+	is.Eq(color.RGBA{R: 128, A: 0xff}, img.RGBAAt(16+1*2, 16+2*2))
+	// 256 color
+	is.Eq(color.RGBA{R: 255, G: 135, A: 0xff}, img.RGBAAt(16+2*2, 40+2*2))
+	// wide char is a box of two cells
+	is.Eq(color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, img.RGBAAt(16+32+2*2, 40+3*2))
+	is.Eq(color.RGBA{R: 1, G: 2, B: 3, A: 0xff}, img.RGBAAt(16+32+13*2, 40+3*2))
+}
+
+func TestImage_options(t *testing.T) {
+	is := assert.New(t)
+
+	img := render.Image("\x1b[7;4m_\x1b[0m\x1b[2m_\x1b[8m_", render.ImageOptions{
+		Theme:   render.LightTheme,
+		Scale:   1,
+		Padding: 2,
+		Columns: 5,
+	})
+	is.Eq(5*8+4, img.Bounds().Dx())
+	is.Eq(12+4, img.Bounds().Dy())
+
+	white, black := color.RGBA{R: 255, G: 255, B: 255, A: 0xff}, color.RGBA{A: 0xff}
+	// reverse: black bg, white underline
+	is.Eq(black, img.RGBAAt(2, 2))
+	is.Eq(white, img.RGBAAt(2, 2+10))
+	// dim: "_" is on the last glyph row
+	is.Eq(color.RGBA{R: 127, G: 127, B: 127, A: 0xff}, img.RGBAAt(2+8, 2+9))
+	// hidden
+	is.Eq(white, img.RGBAAt(2+16, 2+9))
+}
+
+func TestPNG(t *testing.T) {
+	is := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	is.NoErr(render.PNG(buf, "\x1b[32mok\x1b[0m", render.ImageOptions{Scale: 1}))
+
+	img, err := png.Decode(buf)
+	is.NoErr(err)
+	is.Eq(2*8+16, img.Bounds().Dx())
+}
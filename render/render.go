@@ -0,0 +1,248 @@
+// Package render provide converters for render the ANSI colored text to HTML, SVG and image.
+//
+// It supports the SGR codes emitted by the color package: 16, 256 and RGB colors,
+// bold, dim, italic, underline, blink, reverse, hidden and strikethrough.
+// The other escape sequences are ignored.
+//
+// Usage:
+//
+//	html := render.HTML(color.Red.Sprint("error"), render.HTMLOptions{})
+package render
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/gookit/color/ansi"
+)
+
+// Theme the colors for render the text.
+type Theme struct {
+	Name string
+	// Foreground default text color
+	Foreground color.RGBColor
+	// Background default background color
+	Background color.RGBColor
+	// Palette the 16 basic colors. 0-7 normal, 8-15 bright
+	Palette [16]color.RGBColor
+}
+
+// DefaultTheme dark theme, use the xterm palette
+var DefaultTheme = newTheme("default", color.RGB(229, 229, 229), color.RGB(0, 0, 0))
+
+// LightTheme light background theme
+var LightTheme = newTheme("light", color.RGB(0, 0, 0), color.RGB(255, 255, 255))
+
+func newTheme(name string, fg, bg color.RGBColor) *Theme {
+	t := &Theme{Name: name, Foreground: fg, Background: bg}
+	for i := range t.Palette {
+		t.Palette[i] = color.RGBFromSlice(color.C256ToRgb(uint8(i)))
+	}
+	return t
+}
+
+// Color resolve the color to RGB, returns def if the color is not set.
+func (t *Theme) Color(c ansi.Color, def color.RGBColor) color.RGBColor {
+	switch c.Type {
+	case ansi.ColorBasic:
+		return t.Palette[c.Index&15]
+	case ansi.Color256:
+		if c.Index < 16 {
+			return t.Palette[c.Index]
+		}
+		return xterm256(c.Index)
+	case ansi.ColorRGB:
+		return color.RGB(c.R, c.G, c.B)
+	}
+	return def
+}
+
+// xterm color cube levels of the 256 colors 16 - 231
+var xtermLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// xterm256 convert the 256 color 16 - 255 to RGB by the xterm palette.
+// not use color.C256ToRgb(), its table has adjusted values. eg: 16 is "000001"
+func xterm256(val uint8) color.RGBColor {
+	if val >= 232 { // grayscale ramp
+		v := 8 + (val-232)*10
+		return color.RGB(v, v, v)
+	}
+
+	i := val - 16
+	return color.RGB(xtermLevels[i/36], xtermLevels[i/6%6], xtermLevels[i%6])
+}
+
+// Style the text style parsed from the SGR codes
+type Style struct {
+	Fg, Bg ansi.Color
+
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+	Blink     bool
+	Reverse   bool
+	Hidden    bool
+	Strike    bool
+}
+
+// IsZero check the style is default
+func (s Style) IsZero() bool { return s == Style{} }
+
+// Colors resolve the fg and bg colors by the theme, the reverse is applied.
+func (s Style) Colors(t *Theme) (fg, bg color.RGBColor) {
+	fg = t.Color(s.Fg, t.Foreground)
+	bg = t.Color(s.Bg, t.Background)
+	if s.Reverse {
+		fg, bg = bg, fg
+	}
+	return
+}
+
+// apply the SGR attributes to the style
+func (s *Style) apply(attrs []ansi.Attr) {
+	for _, a := range attrs {
+		switch a.Code {
+		case 0:
+			*s = Style{}
+		case 1:
+			s.Bold = true
+		case 2:
+			s.Dim = true
+		case 3:
+			s.Italic = true
+		case 4: // "4:0" is no underline
+			s.Underline = len(a.Sub) == 0 || a.Sub[0] != 0
+		case 5, 6:
+			s.Blink = true
+		case 7:
+			s.Reverse = true
+		case 8:
+			s.Hidden = true
+		case 9:
+			s.Strike = true
+		case 21:
+			s.Underline = true
+		case 22:
+			s.Bold, s.Dim = false, false
+		case 23:
+			s.Italic = false
+		case 24:
+			s.Underline = false
+		case 25:
+			s.Blink = false
+		case 27:
+			s.Reverse = false
+		case 28:
+			s.Hidden = false
+		case 29:
+			s.Strike = false
+		case 39:
+			s.Fg = ansi.Color{}
+		case 49:
+			s.Bg = ansi.Color{}
+		default:
+			if a.IsFg() {
+				s.Fg = a.Color
+			} else if a.IsBg() {
+				s.Bg = a.Color
+			}
+		}
+	}
+}
+
+// Span a text with style
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Line a line of the spans
+type Line []Span
+
+// Width get the display width of the line. see color.VisibleWidth()
+func (l Line) Width() (w int) {
+	for _, sp := range l {
+		w += color.VisibleWidth(sp.Text)
+	}
+	return
+}
+
+// Parse the ANSI text to lines of styled spans. the style is kept across lines.
+func Parse(s string) []Line {
+	var style Style
+	lines := []Line{nil}
+
+	lx := ansi.NewLexer(strings.Replace(s, "\r\n", "\n", -1))
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		switch tok.Kind {
+		case ansi.KindSGR:
+			style.apply(tok.SGR())
+			continue
+		case ansi.KindText:
+		default:
+			continue
+		}
+
+		for i, text := range strings.Split(tok.Raw, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if text == "" {
+				continue
+			}
+
+			ln := &lines[len(lines)-1]
+			// merge to the last span if same style
+			if n := len(*ln); n > 0 && (*ln)[n-1].Style == style {
+				(*ln)[n-1].Text += text
+			} else {
+				*ln = append(*ln, Span{Text: text, Style: style})
+			}
+		}
+	}
+	return lines
+}
+
+// TabWidth for expand the tabs on render SVG and image
+const TabWidth = 8
+
+// expand the tabs in the text to spaces, col is the column of the text start.
+func expandTabs(text string, col int) string {
+	if strings.IndexByte(text, '\t') < 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	for _, r := range text {
+		if r == '\t' {
+			n := TabWidth - col%TabWidth
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+
+		sb.WriteRune(r)
+		col += color.VisibleWidth(string(r))
+	}
+	return sb.String()
+}
+
+// split the lines to cells: expand the tabs and compute the column of each span.
+func layoutLines(lines []Line) (cols [][]int, maxCol int) {
+	cols = make([][]int, len(lines))
+	for i, line := range lines {
+		var col int
+		cols[i] = make([]int, len(line))
+		for j := range line {
+			line[j].Text = expandTabs(line[j].Text, col)
+			cols[i][j] = col
+			col += color.VisibleWidth(line[j].Text)
+		}
+
+		if col > maxCol {
+			maxCol = col
+		}
+	}
+	return
+}
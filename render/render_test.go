@@ -0,0 +1,62 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/gookit/assert"
+	"github.com/gookit/color"
+	"github.com/gookit/color/ansi"
+	"github.com/gookit/color/render"
+)
+
+func TestParse(t *testing.T) {
+	is := assert.New(t)
+
+	lines := render.Parse("a\x1b[1;31mb\nc\x1b[0m\x1b[2Kd\r\n\x1b[38;5;208;48;2;1;2;3me")
+	is.Len(lines, 3)
+
+	is.Len(lines[0], 2)
+	is.Eq("a", lines[0][0].Text)
+	is.True(lines[0][0].Style.IsZero())
+	is.Eq("b", lines[0][1].Text)
+	is.True(lines[0][1].Style.Bold)
+	is.Eq(ansi.Color{Type: ansi.ColorBasic, Index: 1}, lines[0][1].Style.Fg)
+
+	// style is kept across lines, the other escapes are ignored
+	is.Len(lines[1], 2)
+	is.Eq("c", lines[1][0].Text)
+	is.True(lines[1][0].Style.Bold)
+	is.Eq("d", lines[1][1].Text)
+	is.True(lines[1][1].Style.IsZero())
+	is.Eq(2, lines[1].Width())
+
+	st := lines[2][0].Style
+	is.Eq(ansi.Color{Type: ansi.Color256, Index: 208}, st.Fg)
+	fg, bg := st.Colors(render.DefaultTheme)
+	is.Eq("ff8700", fg.Hex())
+	is.Eq("010203", bg.Hex())
+
+	// reverse, no underline
+	st = render.Parse("\x1b[4;7;4:0mx")[0][0].Style
+	is.True(st.Reverse)
+	is.False(st.Underline)
+	fg, bg = st.Colors(render.DefaultTheme)
+	is.Eq(render.DefaultTheme.Background, fg)
+	is.Eq(render.DefaultTheme.Foreground, bg)
+}
+
+func TestTheme_Color(t *testing.T) {
+	is := assert.New(t)
+	th := render.DefaultTheme
+
+	def := color.RGB(1, 1, 1)
+	is.Eq(def, th.Color(ansi.Color{}, def))
+	is.Eq("800000", th.Color(ansi.Color{Type: ansi.ColorBasic, Index: 1}, def).Hex())
+	is.Eq("ff0000", th.Color(ansi.Color{Type: ansi.Color256, Index: 9}, def).Hex())
+	is.Eq("ff8700", th.Color(ansi.Color{Type: ansi.Color256, Index: 208}, def).Hex())
+	is.Eq("000000", th.Color(ansi.Color{Type: ansi.Color256, Index: 16}, def).Hex())
+	is.Eq("ff0000", th.Color(ansi.Color{Type: ansi.Color256, Index: 196}, def).Hex())
+	is.Eq("ffffff", th.Color(ansi.Color{Type: ansi.Color256, Index: 231}, def).Hex())
+	is.Eq("808080", th.Color(ansi.Color{Type: ansi.Color256, Index: 244}, def).Hex())
+	is.Eq("0a0b0c", th.Color(ansi.Color{Type: ansi.ColorRGB, R: 10, G: 11, B: 12}, def).Hex())
+}
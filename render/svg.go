@@ -0,0 +1,168 @@
+package render
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gookit/color"
+)
+
+// SVGOptions for the SVG()
+type SVGOptions struct {
+	// Theme for resolve the colors, default is DefaultTheme
+	Theme *Theme
+	// FontSize in px, default is 14
+	FontSize float64
+	// FontFamily default is "Menlo, Monaco, Consolas, 'DejaVu Sans Mono', monospace"
+	FontFamily string
+	// LineHeight multiple of the font size, default is 1.4
+	LineHeight float64
+	// Title show on the window title bar
+	Title string
+	// NoWindow do not draw the window title bar and rounded border
+	NoWindow bool
+	// Columns the min width by columns, default is the max width of the lines.
+	Columns int
+}
+
+func (o *SVGOptions) init() {
+	if o.Theme == nil {
+		o.Theme = DefaultTheme
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 14
+	}
+	if o.FontFamily == "" {
+		o.FontFamily = "Menlo, Monaco, Consolas, 'DejaVu Sans Mono', monospace"
+	}
+	if o.LineHeight <= 0 {
+		o.LineHeight = 1.4
+	}
+}
+
+// SVG render the ANSI colored text to a standalone SVG image of a terminal window.
+//
+// Usage:
+//
+//	svg := render.SVG(output, render.SVGOptions{Title: "demo"})
+//	os.WriteFile("demo.svg", []byte(svg), 0644)
+func SVG(s string, opts SVGOptions) string {
+	opts.init()
+	t := opts.Theme
+
+	lines := Parse(strings.TrimSuffix(s, "\n"))
+	cols, maxCol := layoutLines(lines)
+	if opts.Columns > maxCol {
+		maxCol = opts.Columns
+	}
+
+	// sizes
+	fs := opts.FontSize
+	charW, lineH := fs*0.6, fs*opts.LineHeight
+	pad, top := fs, fs
+	if !opts.NoWindow {
+		top += fs * 2
+	}
+	width := float64(maxCol)*charW + pad*2
+	height := float64(len(lines))*lineH + top + pad
+
+	var sb strings.Builder
+	sb.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + fnum(width) + `" height="` + fnum(height) +
+		`" viewBox="0 0 ` + fnum(width) + " " + fnum(height) + `" font-family="` + html.EscapeString(xmlText(opts.FontFamily)) +
+		`" font-size="` + fnum(fs) + `">` + "\n")
+
+	if opts.NoWindow {
+		sb.WriteString(`<rect width="100%" height="100%" fill="#` + t.Background.Hex() + `"/>` + "\n")
+	} else {
+		sb.WriteString(`<rect width="100%" height="100%" rx="6" fill="#` + t.Background.Hex() + `"/>` + "\n")
+		r := fs * 0.4
+		for i, c := range []string{"ff5f56", "ffbd2e", "27c93f"} {
+			sb.WriteString(`<circle cx="` + fnum(pad+r+float64(i)*r*3) + `" cy="` + fnum(fs) + `" r="` + fnum(r) + `" fill="#` + c + `"/>` + "\n")
+		}
+
+		if opts.Title != "" {
+			sb.WriteString(`<text x="50%" y="` + fnum(fs*1.35) + `" text-anchor="middle" fill="#` + t.Foreground.Hex() +
+				`" fill-opacity="0.7">` + html.EscapeString(xmlText(opts.Title)) + "</text>\n")
+		}
+	}
+
+	// the background rects, then the texts
+	var texts strings.Builder
+	sb.WriteString(`<g transform="translate(` + fnum(pad) + "," + fnum(top) + `)">` + "\n")
+	for i, line := range lines {
+		y := float64(i) * lineH
+		baseline := y + (lineH-fs)/2 + fs*0.8
+
+		for j, sp := range line {
+			x := float64(cols[i][j]) * charW
+			fg, bg := sp.Style.Colors(t)
+			if sp.Style.Bg.Type != 0 || sp.Style.Reverse {
+				w := float64(color.VisibleWidth(sp.Text)) * charW
+				sb.WriteString(`<rect x="` + fnum(x) + `" y="` + fnum(y) + `" width="` + fnum(w) + `" height="` + fnum(lineH) +
+					`" fill="#` + bg.Hex() + `"/>` + "\n")
+			}
+
+			text := xmlText(sp.Text)
+			if sp.Style.Hidden || strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			texts.WriteString(`<text x="` + fnum(x) + `" y="` + fnum(baseline) + `" fill="#` + fg.Hex() + `"`)
+			texts.WriteString(svgTextAttrs(sp.Style))
+			// the wide chars width is not same in fonts, fit it to the columns
+			if w := color.VisibleWidth(text); w != utf8.RuneCountInString(text) {
+				texts.WriteString(` textLength="` + fnum(float64(w)*charW) + `" lengthAdjust="spacingAndGlyphs"`)
+			}
+			texts.WriteString(` xml:space="preserve">` + html.EscapeString(text) + "</text>\n")
+		}
+	}
+
+	sb.WriteString(texts.String())
+	sb.WriteString("</g>\n</svg>\n")
+	return sb.String()
+}
+
+// the font and decoration attributes of the text
+func svgTextAttrs(s Style) string {
+	var sb strings.Builder
+	if s.Bold {
+		sb.WriteString(` font-weight="bold"`)
+	}
+	if s.Italic {
+		sb.WriteString(` font-style="italic"`)
+	}
+	if s.Dim {
+		sb.WriteString(` fill-opacity="0.5"`)
+	}
+
+	var decors []string
+	if s.Underline {
+		decors = append(decors, "underline")
+	}
+	if s.Strike {
+		decors = append(decors, "line-through")
+	}
+	if len(decors) > 0 {
+		sb.WriteString(` text-decoration="` + strings.Join(decors, " ") + `"`)
+	}
+	return sb.String()
+}
+
+// format the number for SVG attributes, keep 2 decimals. eg: 8.4, 14
+func fnum(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// drop the chars not allowed in XML 1.0. eg: the control chars BEL, BS and a lone ESC of an incomplete sequence
+func xmlText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' || r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, s)
+}
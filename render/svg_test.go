@@ -0,0 +1,75 @@
+package render_test
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gookit/assert"
+	"github.com/gookit/color/render"
+)
+
+func TestSVG(t *testing.T) {
+	is := assert.New(t)
+
+	s := render.SVG("\x1b[1;32mok\x1b[0m\tdone <x>\n\x1b[41m err \x1b[0m 你好\n", render.SVGOptions{Title: "demo & test"})
+	is.True(strings.HasPrefix(s, `<svg xmlns="http://www.w3.org/2000/svg" width="162.4" height="95.2" viewBox="0 0 162.4 95.2"`))
+	is.Contains(s, ` font-size="14">`)
+	// window
+	is.Contains(s, `<rect width="100%" height="100%" rx="6" fill="#000000"/>`)
+	is.Contains(s, `<circle cx="19.6" cy="14" r="5.6" fill="#ff5f56"/>`)
+	is.Contains(s, `>demo &amp; test</text>`)
+
+	// texts and background
+	is.Contains(s, `<text x="0" y="14" fill="#008000" font-weight="bold" xml:space="preserve">ok</text>`)
+	is.Contains(s, `<text x="16.8" y="14" fill="#e5e5e5" xml:space="preserve">      done &lt;x&gt;</text>`)
+	is.Contains(s, `<rect x="0" y="19.6" width="42" height="19.6" fill="#800000"/>`)
+	is.Contains(s, `<text x="42" y="33.6" fill="#e5e5e5" textLength="42" lengthAdjust="spacingAndGlyphs" xml:space="preserve"> 你好</text>`)
+
+	// valid XML
+	var v struct{}
+	is.NoErr(xml.Unmarshal([]byte(s), &v))
+}
+
+func TestSVG_controlChars(t *testing.T) {
+	is := assert.New(t)
+
+	s := render.SVG("a\x07b\x08c \x1b[31mred\x1b[0m \x1bx\x1b[", render.SVGOptions{Title: "t\x07"})
+	is.Contains(s, `<text x="0" y="14" fill="#e5e5e5" xml:space="preserve">abc </text>`)
+	is.NotContains(s, "\x07")
+	is.NotContains(s, "\x08")
+	is.NotContains(s, "\x1b")
+
+	// the output is valid XML
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		is.NoErr(err)
+		if err != nil {
+			break
+		}
+	}
+}
+
+func TestSVG_options(t *testing.T) {
+	is := assert.New(t)
+
+	s := render.SVG("\x1b[3;4;9;2;7mx\x1b[0m \x1b[8mhidden", render.SVGOptions{
+		Theme:      render.LightTheme,
+		FontSize:   10,
+		LineHeight: 2,
+		NoWindow:   true,
+		Columns:    20,
+	})
+	is.True(strings.HasPrefix(s, `<svg xmlns="http://www.w3.org/2000/svg" width="140" height="40" viewBox="0 0 140 40"`))
+	is.Contains(s, `<rect width="100%" height="100%" fill="#ffffff"/>`)
+	is.NotContains(s, "<circle")
+	// reverse
+	is.Contains(s, `<rect x="0" y="0" width="6" height="20" fill="#000000"/>`)
+	is.Contains(s, `<text x="0" y="13" fill="#ffffff" font-style="italic" fill-opacity="0.5" text-decoration="underline line-through" xml:space="preserve">x</text>`)
+	is.NotContains(s, "hidden")
+}
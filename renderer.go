@@ -0,0 +1,484 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Renderer can render and print colored text by its own settings.
+//
+// Each renderer has independent color level, output writer, tag parser and style registry.
+// So a program can write colored text to stdout and plain text to a log file at the same time.
+//
+// Usage:
+//
+//	r := color.NewRenderer(os.Stdout)
+//	r.Println("<info>hello</> world")
+//
+//	// write plain text to a log file
+//	lr := color.NewRenderer(logFile)
+//	lr.Disable()
+//	lr.Println("<info>hello</> world") // output: "hello world"
+//
+// The package-level functions such as Print, Sprintf, RenderCode are
+// delegate to the default renderer, see Std(). The methods of the color
+// types, eg: Color.Print, Style.AppendRender also use the default renderer,
+// use Renderer.RenderCode or Renderer.AppendStyle to render them by a renderer.
+//
+// Notice: the Renderer has no lock, please configure it before use.
+// It must not be reconfigured while it is used by other goroutines.
+type Renderer struct {
+	// enable and renderTag is pointer, the default renderer will point to
+	// the package var Enable and RenderTag. keep them are configurable.
+	enable    *bool
+	renderTag *bool
+	// color level for render
+	level Level
+	// link the terminal supports OSC 8 hyperlinks. see Link()
+	link bool
+	// output the default io.Writer for print message
+	output io.Writer
+	// detectWriter on print messages, if the writer is not a terminal,
+	// will strip color codes and tags. see SetDetectWriter()
+	detectWriter bool
+	// parser for parse color tags
+	parser *TagParser
+	// styles registry, use for the Tag
+	styles map[string]Style
+}
+
+// NewRenderer create a new Renderer with output writer.
+//
+// The color level will use the detected level of current terminal,
+// can be changed by Renderer.SetLevel()
+func NewRenderer(w io.Writer) *Renderer {
+	enable, renderTag := Enable, true
+
+	r := &Renderer{
+		enable:    &enable,
+		renderTag: &renderTag,
+		level:     std.level,
+		link:      std.link,
+		output:    w,
+		styles:    make(map[string]Style, len(Styles)),
+	}
+
+	// copy the internal styles
+	for name, s := range Styles {
+		r.styles[name] = s
+	}
+
+	r.parser = &TagParser{rd: r}
+	return r
+}
+
+// create the default renderer. it uses the package vars Enable and RenderTag.
+func newStdRenderer(level Level, w io.Writer) *Renderer {
+	r := &Renderer{
+		enable:    &Enable,
+		renderTag: &RenderTag,
+		level:     level,
+		link:      supportLink,
+		output:    w,
+		parser:    &tagParser,
+		styles:    Styles,
+	}
+
+	tagParser.rd = r
+	return r
+}
+
+/*************************************************************
+ * renderer settings
+ *************************************************************/
+
+// IsEnabled check color render is enabled
+func (r *Renderer) IsEnabled() bool { return *r.enable }
+
+// SetEnable set color render enable, returns old value.
+func (r *Renderer) SetEnable(enable bool) bool {
+	oldVal := *r.enable
+	*r.enable = enable
+	return oldVal
+}
+
+// Disable color render, returns old value.
+func (r *Renderer) Disable() bool { return r.SetEnable(false) }
+
+// NotRenderTag on call Renderer.Print, Renderer.Sprint ...
+func (r *Renderer) NotRenderTag() { *r.renderTag = false }
+
+// SetRenderTag set render color tag on print messages
+func (r *Renderer) SetRenderTag(renderTag bool) { *r.renderTag = renderTag }
+
+// Level get the color level of the renderer
+func (r *Renderer) Level() Level { return r.level }
+
+// SetLevel set the color level for render, returns old level.
+func (r *Renderer) SetLevel(level Level) Level {
+	oldVal := r.level
+	r.level = level
+	return oldVal
+}
+
+// SupportColor check the renderer color level is supports color output
+func (r *Renderer) SupportColor() bool { return r.level > LevelNo }
+
+// SupportLink check the renderer will output OSC 8 hyperlinks. see Link()
+func (r *Renderer) SupportLink() bool { return r.link }
+
+// SetSupportLink set the terminal supports OSC 8 hyperlinks, returns old value.
+func (r *Renderer) SetSupportLink(support bool) bool {
+	oldVal := r.link
+	r.link = support
+	return oldVal
+}
+
+// Output get the output writer
+func (r *Renderer) Output() io.Writer { return r.output }
+
+// SetOutput set the output writer for print messages
+func (r *Renderer) SetOutput(w io.Writer) { r.output = w }
+
+// SetDetectWriter set detect the writer on print messages.
+//
+// If enabled, the Print*, Fprint* methods will check the writer is a terminal,
+// if not(eg: bytes.Buffer, pipe, file), will strip color codes and tags on output.
+func (r *Renderer) SetDetectWriter(enable bool) { r.detectWriter = enable }
+
+// check the writer can output color. see SetDetectWriter()
+func (r *Renderer) canRenderTo(w io.Writer) bool {
+	return !r.detectWriter || IsTerminalWriter(w)
+}
+
+// replace color tags for output to the writer.
+func (r *Renderer) replaceTagTo(w io.Writer, str string) string {
+	if r.canRenderTo(w) {
+		return r.ReplaceTag(str)
+	}
+
+	// strip color tags and codes
+	if *r.renderTag {
+		str = r.parser.clearTags(str)
+	}
+	return ClearCode(str)
+}
+
+// TagParser get the tag parser of the renderer
+func (r *Renderer) TagParser() *TagParser { return r.parser }
+
+// AddStyle add a style to the renderer
+func (r *Renderer) AddStyle(name string, s Style) { r.styles[name] = s }
+
+// GetStyle get defined style by name
+func (r *Renderer) GetStyle(name string) Style {
+	if s, ok := r.styles[name]; ok {
+		return s
+	}
+
+	if realName, ok := styleAliases[name]; ok {
+		return r.styles[realName]
+	}
+
+	// empty style
+	return New()
+}
+
+// can render color code
+func (r *Renderer) canRender() bool { return *r.enable && r.level > LevelNo }
+
+/*************************************************************
+ * render color code
+ *************************************************************/
+
+// RenderCode render message by color code.
+//
+// Usage:
+//
+//	msg := r.RenderCode("3;32;45", "some", "message")
+func (r *Renderer) RenderCode(code string, args ...any) string {
+	var message string
+
+	// Fast path optimizations
+	if ln := len(args); ln == 1 {
+		// Single argument - avoid fmt.Sprint overhead
+		if str, ok := args[0].(string); ok {
+			message = str
+		} else {
+			message = fmt.Sprint(args[0])
+		}
+	} else if ln == 2 {
+		// Two arguments - common case, try to optimize if both are strings
+		if str1, ok1 := args[0].(string); ok1 {
+			if str2, ok2 := args[1].(string); ok2 {
+				message = str1 + str2
+			} else {
+				message = fmt.Sprint(args...)
+			}
+		} else {
+			message = fmt.Sprint(args...)
+		}
+	} else if ln == 0 {
+		return ""
+	} else {
+		// Multiple arguments - use fmt.Sprint for safety
+		message = fmt.Sprint(args...)
+	}
+
+	if len(code) == 0 {
+		return message
+	}
+
+	// disabled OR not support color
+	if !r.canRender() {
+		return ClearCode(message)
+	}
+
+	// downgrade the code to current color level
+	code = ConvertCodeByLevel(code, r.level)
+	// return fmt.Sprintf(FullColorTpl, code, message)
+	return StartSet + code + "m" + message + ResetSet
+}
+
+// RenderWithSpaces Render code with spaces.
+// If the number of args is > 1, a space will be added between the args
+func (r *Renderer) RenderWithSpaces(code string, args ...any) string {
+	msg := formatLikePrintln(args)
+	if len(code) == 0 {
+		return msg
+	}
+
+	// disabled OR not support color
+	if !r.canRender() {
+		return ClearCode(msg)
+	}
+
+	code = ConvertCodeByLevel(code, r.level)
+	return StartSet + code + "m" + msg + ResetSet
+}
+
+// RenderString render a string with color code.
+//
+// Usage:
+//
+//	msg := r.RenderString("3;32;45", "a message")
+func (r *Renderer) RenderString(code string, str string) string {
+	if len(code) == 0 || str == "" {
+		return str
+	}
+
+	// disabled OR not support color
+	if !r.canRender() {
+		return ClearCode(str)
+	}
+
+	open := StartSet + ConvertCodeByLevel(code, r.level) + "m"
+	// If the string contains reset sequences, re-apply our color after each
+	// reset so that nested colored args don't break the outer color.
+	if strings.Contains(str, ResetSet) {
+		str = strings.ReplaceAll(str, ResetSet, ResetSet+open)
+	}
+	return open + str + ResetSet
+}
+
+// AppendStyle append the str rendered by the style to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+//
+// Usage:
+//
+//	buf = r.AppendStyle(buf[:0], color.Style{color.FgGreen, color.OpBold}, "message")
+func (r *Renderer) AppendStyle(dst []byte, s Style, str string) []byte {
+	dst, start, ok := r.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(Opts(s).appendCode(dst, len(dst)), start, str)
+}
+
+// appendStart append the StartSet to dst for render the str, returns the start index of it.
+// If cannot render, the str is appended to dst directly and returns ok=false.
+func (r *Renderer) appendStart(dst []byte, str string) (_ []byte, start int, ok bool) {
+	if str == "" {
+		return dst, 0, false
+	}
+
+	// disabled OR not support color
+	if !r.canRender() {
+		return append(dst, ClearCode(str)...), 0, false
+	}
+
+	start = len(dst)
+	return append(dst, StartSet...), start, true
+}
+
+// appendRenderEnd append the "m", str and ResetSet to dst, dst[start:] is the StartSet and color code.
+// Like RenderString, the color will be re-applied after each reset in the str.
+func appendRenderEnd(dst []byte, start int, str string) []byte {
+	// empty code, only append the str
+	if len(dst) == start+len(StartSet) {
+		return append(dst[:start], str...)
+	}
+
+	dst = append(dst, 'm')
+	end := len(dst)
+	for {
+		i := strings.Index(str, ResetSet)
+		if i < 0 {
+			break
+		}
+
+		i += len(ResetSet)
+		dst = append(dst, str[:i]...)
+		dst = append(dst, dst[start:end]...)
+		str = str[i:]
+	}
+
+	dst = append(dst, str...)
+	return append(dst, ResetSet...)
+}
+
+// appendCodeSep append the ";" to dst if dst[start:] is not empty
+func appendCodeSep(dst []byte, start int) []byte {
+	if len(dst) > start {
+		return append(dst, ';')
+	}
+	return dst
+}
+
+// ReplaceTag parse string, replace color tag and return rendered string
+func (r *Renderer) ReplaceTag(str string) string {
+	return r.parser.ParseByEnv(str)
+}
+
+// SetTerminal by given code.
+func (r *Renderer) SetTerminal(code string) error {
+	if !r.canRender() {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(r.output, SettingTpl, ConvertCodeByLevel(code, r.level))
+	return err
+}
+
+// ResetTerminal terminal setting.
+func (r *Renderer) ResetTerminal() error {
+	if !r.canRender() {
+		return nil
+	}
+
+	_, err := fmt.Fprint(r.output, ResetSet)
+	return err
+}
+
+/*************************************************************
+ * print methods(will auto parse color tags)
+ *************************************************************/
+
+// Print render color tag and print messages
+func (r *Renderer) Print(a ...any) {
+	r.Fprint(r.output, a...)
+}
+
+// Printf format and print messages
+func (r *Renderer) Printf(format string, a ...any) {
+	r.Fprintf(r.output, format, a...)
+}
+
+// Println messages with new line
+func (r *Renderer) Println(a ...any) {
+	r.Fprintln(r.output, a...)
+}
+
+// Fprint print rendered messages to writer
+//
+// Notice: will ignore print error
+func (r *Renderer) Fprint(w io.Writer, a ...any) {
+	if len(a) == 0 {
+		return
+	}
+
+	_, err := fmt.Fprint(w, r.replaceTagTo(w, fmt.Sprint(a...)))
+	saveInternalError(err)
+}
+
+// Fprintf print format and rendered messages to writer.
+// Notice: will ignore print error
+func (r *Renderer) Fprintf(w io.Writer, format string, a ...any) {
+	str := fmt.Sprintf(format, a...)
+	_, err := fmt.Fprint(w, r.replaceTagTo(w, str))
+	saveInternalError(err)
+}
+
+// Fprintln print rendered messages line to writer
+// Notice: will ignore print error
+func (r *Renderer) Fprintln(w io.Writer, a ...any) {
+	str := formatLikePrintln(a)
+	_, err := fmt.Fprintln(w, r.replaceTagTo(w, str))
+	saveInternalError(err)
+}
+
+// Lprint passes colored messages to a log.Logger for printing.
+func (r *Renderer) Lprint(l *log.Logger, a ...any) {
+	l.Print(r.Render(a...))
+}
+
+// Render parse color tags, return rendered string.
+//
+// Usage:
+//
+//	text := r.Render("<info>hello</> <cyan>world</>!")
+//	fmt.Println(text)
+func (r *Renderer) Render(a ...any) string {
+	if len(a) == 0 {
+		return ""
+	}
+	return r.ReplaceTag(fmt.Sprint(a...))
+}
+
+// Sprint parse color tags, return rendered string
+func (r *Renderer) Sprint(a ...any) string {
+	if len(a) == 0 {
+		return ""
+	}
+	return r.ReplaceTag(fmt.Sprint(a...))
+}
+
+// Sprintf format and return rendered string
+func (r *Renderer) Sprintf(format string, a ...any) string {
+	return r.ReplaceTag(fmt.Sprintf(format, a...))
+}
+
+// Tag render messages by a defined style or tag name.
+//
+// Usage:
+//
+//	r.Tag("info", "message")
+func (r *Renderer) Tag(name string, a ...any) string {
+	if stl := r.GetStyle(name); !stl.IsEmpty() {
+		return r.RenderCode(stl.String(), a...)
+	}
+	return r.RenderCode(r.parser.tagCode(name, false), a...)
+}
+
+// print message with code, support render full color code on pwsh.exe, cmd.exe
+func (r *Renderer) doPrint(code, str string) {
+	_, err := fmt.Fprint(r.output, r.renderStringTo(r.output, code, str))
+	saveInternalError(err)
+}
+
+// print message line with code, support render full color code on pwsh.exe, cmd.exe
+func (r *Renderer) doPrintln(code string, args []any) {
+	str := formatLikePrintln(args)
+	_, err := fmt.Fprintln(r.output, r.renderStringTo(r.output, code, str))
+	saveInternalError(err)
+}
+
+// render string with color code for output to the writer.
+func (r *Renderer) renderStringTo(w io.Writer, code, str string) string {
+	if r.canRenderTo(w) {
+		return r.RenderString(code, str)
+	}
+	return ClearCode(str)
+}
@@ -0,0 +1,209 @@
+package color
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestNewRenderer(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	r := NewRenderer(buf)
+	r.SetLevel(LevelRgb)
+	r.SetEnable(true)
+	is.True(r.IsEnabled())
+	is.True(r.SupportColor())
+	is.Eq(LevelRgb, r.Level())
+	is.Eq(buf, r.Output())
+	is.NotNil(r.TagParser())
+
+	r.Print("<red>MSG</>")
+	is.Eq("\x1b[0;31mMSG\x1b[0m", buf.String())
+	buf.Reset()
+
+	r.Printf("<red>%s</>", "MSG")
+	is.Eq("\x1b[0;31mMSG\x1b[0m", buf.String())
+	buf.Reset()
+
+	r.Println("<red>hello</>", "world")
+	is.Eq("\x1b[0;31mhello\x1b[0m world\n", buf.String())
+	buf.Reset()
+
+	logger := log.New(buf, "", 0)
+	r.Lprint(logger, "<red>MSG</>")
+	is.Eq("\x1b[0;31mMSG\x1b[0m\n", buf.String())
+	buf.Reset()
+
+	is.Eq("", r.Sprint())
+	is.Eq("", r.Render())
+	is.Eq("\x1b[0;31mMSG\x1b[0m", r.Sprint("<red>MSG</>"))
+	is.Eq("\x1b[0;31mMSG\x1b[0m", r.Render("<red>MSG</>"))
+	is.Eq("\x1b[0;31mMSG\x1b[0m", r.Sprintf("<red>%s</>", "MSG"))
+	is.Eq("\x1b[38;2;204;123;56mMSG\x1b[0m", r.RenderCode("38;2;204;123;56", "MSG"))
+	is.Eq("\x1b[32mM G\x1b[0m", r.RenderWithSpaces("32", "M", "G"))
+	is.Eq("\x1b[32mMSG\x1b[0m", r.RenderString("32", "MSG"))
+
+	// set terminal
+	is.NoErr(r.SetTerminal("32"))
+	is.NoErr(r.ResetTerminal())
+	is.Eq("\x1b[32m\x1b[0m", buf.String())
+	buf.Reset()
+
+	// downgrade by renderer level
+	r.SetLevel(Level256)
+	is.Eq("\x1b[38;5;173mMSG\x1b[0m", r.RenderCode("38;2;204;123;56", "MSG"))
+	is.Eq("\x1b[38;5;39mMSG\x1b[0m", r.Sprint("<deepskyblue>MSG</>"))
+
+	// not render tag
+	r.NotRenderTag()
+	is.Eq("<red>MSG</>", r.Sprint("<red>MSG</>"))
+	r.SetRenderTag(true)
+
+	// disable
+	is.True(r.Disable())
+	is.False(r.IsEnabled())
+	is.Eq("MSG", r.Sprint("<red>MSG</>"))
+	is.Eq("MSG", r.RenderCode("32", "MSG"))
+	is.NoErr(r.SetTerminal("32"))
+	is.Eq("", buf.String())
+}
+
+func TestRenderer_independent(t *testing.T) {
+	is := assert.New(t)
+	cBuf, pBuf := new(bytes.Buffer), new(bytes.Buffer)
+
+	colored := NewRenderer(cBuf)
+	colored.SetLevel(Level16)
+	plain := NewRenderer(pBuf)
+	plain.SetLevel(LevelNo)
+
+	colored.Println("<info>hello</> world")
+	plain.Println("<info>hello</> world")
+	is.Eq("\x1b[0;32mhello\x1b[0m world\n", cBuf.String())
+	is.Eq("hello world\n", pBuf.String())
+
+	// not affect the std renderer
+	is.Eq(std.output, Std().Output())
+	is.NotEq(cBuf, Std().Output())
+	is.NotEq(plain.TagParser(), Std().TagParser())
+
+	// render the style by the renderer
+	s := Style{FgGreen, OpBold}
+	colored.SetEnable(true)
+	is.Eq("\x1b[32;1mmsg\x1b[0m", string(colored.AppendStyle(nil, s, "msg")))
+	is.Eq("msg", string(plain.AppendStyle(nil, s, "msg")))
+}
+
+func TestRenderer_styles(t *testing.T) {
+	is := assert.New(t)
+
+	r := NewRenderer(new(bytes.Buffer))
+	r.SetLevel(Level16)
+	r.SetEnable(true)
+
+	is.Eq("\x1b[0;32mMSG\x1b[0m", r.Tag("info", "MSG"))
+	is.Eq("\x1b[1;32mMSG\x1b[0m", r.Tag("suc", "MSG"))
+	is.Eq("\x1b[0;35mMSG\x1b[0m", r.Tag("mga", "MSG"))
+
+	r.AddStyle("custom", Style{FgCyan, OpBold})
+	is.Eq("36;1", r.GetStyle("custom").String())
+	is.Eq("\x1b[36;1mMSG\x1b[0m", r.Tag("custom", "MSG"))
+	is.True(r.GetStyle("not-exist").IsEmpty())
+
+	// use the tags of the renderer parser
+	r.TagParser().RegisterTag("r-title", "1;36")
+	is.Eq("\x1b[1;36mMSG\x1b[0m", r.Tag("r-title", "MSG"))
+	is.Eq("", Std().TagParser().tagCode("r-title", false))
+
+	// not add to the std renderer
+	is.True(Std().GetStyle("custom").IsEmpty())
+	_, ok := Styles["custom"]
+	is.False(ok)
+}
+
+func TestStd_delegate(t *testing.T) {
+	buf := forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	is.Eq(TermColorLevel(), Std().Level())
+	Println("<red>MSG</>")
+	is.Eq("\x1b[0;31mMSG\x1b[0m\n", buf.String())
+	buf.Reset()
+
+	// package var Enable is used by the std renderer
+	Enable = false
+	is.False(Std().IsEnabled())
+	is.Eq("MSG", Sprint("<red>MSG</>"))
+	Enable = true
+	is.True(Std().IsEnabled())
+
+	AddStyle("test-std", Style{FgCyan})
+	is.Eq("36", Std().GetStyle("test-std").String())
+	delete(Styles, "test-std")
+}
+
+func TestRenderer_SetDetectWriter(t *testing.T) {
+	is := assert.New(t)
+	buf := new(bytes.Buffer)
+
+	r := NewRenderer(buf)
+	r.SetLevel(Level16)
+	r.SetEnable(true)
+	r.SetDetectWriter(true)
+
+	// buffer is not a terminal, will strip color tags and codes
+	r.Fprint(buf, "<red>MSG</> ", Green.Sprint("text"))
+	is.Eq("MSG text", buf.String())
+	buf.Reset()
+
+	r.Fprintf(buf, "<red>%s</>", "MSG")
+	is.Eq("MSG", buf.String())
+	buf.Reset()
+
+	r.Fprintln(buf, "<red>MSG</>", "\x1b[32mtext\x1b[0m")
+	is.Eq("MSG text\n", buf.String())
+	buf.Reset()
+
+	r.Println("<red>MSG</>")
+	is.Eq("MSG\n", buf.String())
+	buf.Reset()
+
+	// not render tag, only strip color codes
+	r.NotRenderTag()
+	r.Print("<red>MSG</> \x1b[32mtext\x1b[0m")
+	is.Eq("<red>MSG</> text", buf.String())
+	buf.Reset()
+	r.SetRenderTag(true)
+
+	// disable detect
+	r.SetDetectWriter(false)
+	r.Fprint(buf, "<red>MSG</>")
+	is.Eq("\x1b[0;31mMSG\x1b[0m", buf.String())
+	buf.Reset()
+}
+
+func TestSetDetectWriter(t *testing.T) {
+	buf := forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	SetDetectWriter(true)
+	defer SetDetectWriter(false)
+
+	Fprint(buf, "<red>MSG</>")
+	is.Eq("MSG", buf.String())
+	buf.Reset()
+
+	Red.Print("MSG")
+	S256(132).Println("MSG")
+	is.Eq("MSGMSG\n", buf.String())
+	buf.Reset()
+
+	// not affect the Sprint
+	is.Eq("\x1b[0;31mMSG\x1b[0m", Sprint("<red>MSG</>"))
+}
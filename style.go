@@ -11,6 +11,8 @@ import (
 
 // Style a 16 color style. can add: fg color, bg color, color options
 //
+// For the underline style and color, please use WithUlStyle(), Style256, RGBStyle or MixStyle.
+//
 // Example:
 //
 //	color.Style{color.FgGreen}.Print("message")
@@ -79,6 +81,16 @@ func (s Style) Code() string { return s.String() }
 // String convert to code string. returns like "32;45;3"
 func (s Style) String() string { return Colors2code(s...) }
 
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+//
+// Usage:
+//
+//	buf = color.Style{color.FgGreen, color.OpBold}.AppendRender(buf[:0], "message")
+//
+// It renders by the default renderer, see Renderer.AppendStyle()
+func (s Style) AppendRender(dst []byte, str string) []byte { return std.AppendStyle(dst, s, str) }
+
 // IsEmpty style
 func (s Style) IsEmpty() bool { return len(s) == 0 }
 
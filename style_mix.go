@@ -0,0 +1,544 @@
+package color
+
+import "fmt"
+
+/*************************************************************
+ * Styler interface
+ *************************************************************/
+
+// Styler the common interface of the style types: Style, *Style256, *RGBStyle and MixStyle
+type Styler interface {
+	// Code get the color code string. eg: "38;5;12;1"
+	Code() string
+	String() string
+	Sprint(a ...any) string
+	Sprintf(format string, a ...any) string
+	Print(a ...any)
+	Printf(format string, a ...any)
+	Println(a ...any)
+	// AppendRender append the rendered str to dst and returns the extended buffer.
+	AppendRender(dst []byte, str string) []byte
+	// ToMix convert to the MixStyle
+	ToMix() MixStyle
+}
+
+// check the style types implement the Styler
+var (
+	_ Styler = Style(nil)
+	_ Styler = (*Style256)(nil)
+	_ Styler = (*RGBStyle)(nil)
+	_ Styler = MixStyle{}
+)
+
+/*************************************************************
+ * MixColor: a 16, 256 or RGB color
+ *************************************************************/
+
+// ColorKind the kind of the MixColor
+type ColorKind uint8
+
+// color kinds of the MixColor
+const (
+	KindNone ColorKind = iota // not set
+	Kind16                    // basic 16 color
+	Kind256                   // 256 color
+	KindRGB                   // RGB true color
+)
+
+// MixColor a color of any kind: 16, 256 or RGB. can be used as fg, bg or underline color.
+//
+// The zero value is not set. it is comparable, can use == to check equal.
+type MixColor struct {
+	kind ColorKind
+	// 16: the fg color code, 256: the color index, RGB: r, g, b
+	val [3]uint8
+}
+
+// Mix16 create a MixColor from the 16 color. the bg color will convert to fg, eg: BgRed -> FgRed
+//
+// returns empty MixColor if c is not a fg or bg color.
+func Mix16(c Color) MixColor {
+	if c.IsBg() {
+		c = c.ToFg()
+	} else if !c.IsFg() {
+		return MixColor{}
+	}
+	return MixColor{kind: Kind16, val: [3]uint8{uint8(c)}}
+}
+
+// Mix256 create a MixColor from the 256 color index
+func Mix256(val uint8) MixColor {
+	return MixColor{kind: Kind256, val: [3]uint8{val}}
+}
+
+// MixRGB create a MixColor from the RGB color values
+func MixRGB(r, g, b uint8) MixColor {
+	return MixColor{kind: KindRGB, val: [3]uint8{r, g, b}}
+}
+
+// MixHex create a MixColor from the hex color string. eg: "ccc", "aabbcc", "#aabbcc"
+//
+// returns empty MixColor on invalid hex string.
+func MixHex(hex string) MixColor {
+	rgb := HEX(hex)
+	if rgb.IsEmpty() {
+		return MixColor{}
+	}
+	return MixRGB(rgb[0], rgb[1], rgb[2])
+}
+
+// Kind get the color kind
+func (c MixColor) Kind() ColorKind { return c.kind }
+
+// IsEmpty the color is not set
+func (c MixColor) IsEmpty() bool { return c.kind == KindNone }
+
+// RGB convert to the RGB color values
+func (c MixColor) RGB() (r, g, b uint8) {
+	switch c.kind {
+	case Kind16:
+		rgb := Color(c.val[0]).RGB()
+		return rgb[0], rgb[1], rgb[2]
+	case Kind256:
+		rgb := Color256{c.val[0]}.RGB()
+		return rgb[0], rgb[1], rgb[2]
+	}
+	return c.val[0], c.val[1], c.val[2]
+}
+
+// index the 256 color index of a 16 or 256 color. eg: FgRed -> 1, FgLightRed -> 9
+func (c MixColor) index() uint8 {
+	if c.kind == Kind16 {
+		if c.val[0] >= HiFgBase {
+			return c.val[0] - HiFgBase + 8
+		}
+		return c.val[0] - FgBase
+	}
+	return c.val[0]
+}
+
+// the role of the color in the style
+const asUl uint8 = 2
+
+// append the color code for the role(AsFg, AsBg, asUl) and color level to dst.
+// the underline color is not supported on Level16.
+func (c MixColor) appendCode(dst []byte, role uint8, level Level) []byte {
+	if role == asUl {
+		if c.kind == KindNone || level == Level16 {
+			return dst
+		}
+		if c.kind == KindRGB && level > Level256 {
+			return RGBColor{c.val[0], c.val[1], c.val[2]}.appendValues(append(dst, UlRGBPfx...))
+		}
+
+		idx := c.index()
+		if c.kind == KindRGB {
+			idx = RgbTo256(c.val[0], c.val[1], c.val[2])
+		}
+		return append(append(dst, Ul256Pfx...), uint8Codes[idx]...)
+	}
+
+	switch c.kind {
+	case Kind16:
+		if role == AsBg {
+			return append(dst, uint8Codes[c.val[0]+DiffFgBg]...)
+		}
+		return append(dst, uint8Codes[c.val[0]]...)
+	case Kind256:
+		return Color256{c.val[0], role}.appendCode(dst, level)
+	case KindRGB:
+		return RGBColor{c.val[0], c.val[1], c.val[2], role}.appendCode(dst, level)
+	}
+	return dst
+}
+
+/*************************************************************
+ * MixStyle: mix the 16, 256 and RGB colors
+ *************************************************************/
+
+// MixStyle a composable style, the fg, bg and underline color can be any kind of the color.
+//
+// Usage:
+//
+//	s := color.MixStyle{Fg: color.MixHex("f90"), Bg: color.Mix16(color.BgBlue), Opts: color.Opts{color.OpBold}}
+//	s.Println("message")
+//
+//	// merge and inherit
+//	title := s.Merge(color.MixStyle{Ul: color.Mix256(196), UlStyle: color.UnderlineCurly})
+type MixStyle struct {
+	// Fg, Bg, Ul the foreground, background and underline color
+	Fg, Bg, Ul MixColor
+	// Opts the color options. eg: OpBold, OpOverline. the off codes(eg: 22) are also allowed
+	Opts Opts
+	// UlStyle the underline style, it is applied after the Opts. eg: UnderlineCurly
+	UlStyle UnderlineStyle
+}
+
+// NewMix create a MixStyle with fg, bg color and options
+func NewMix(fg, bg MixColor, opts ...Color) MixStyle {
+	s := MixStyle{Fg: fg, Bg: bg}
+	s.Opts.Add(opts...)
+	return s
+}
+
+// Merge returns a new style, the colors and options of the other style override the current style.
+//
+// eg: {Fg: red, bold}.Merge({Bg: blue, 22}) -> {Fg: red, Bg: blue}
+func (s MixStyle) Merge(other MixStyle) MixStyle {
+	if other.Fg.IsEmpty() {
+		other.Fg = s.Fg
+	}
+	if other.Bg.IsEmpty() {
+		other.Bg = s.Bg
+	}
+	if other.Ul.IsEmpty() {
+		other.Ul = s.Ul
+	}
+
+	a := s.attrs()
+	a.applyAll(other.Opts, other.UlStyle)
+	other.Opts, other.UlStyle = a.opts(), a.ulStyle
+	return other
+}
+
+// Inherit returns a new style, the unset colors and options are inherited from the parent style.
+//
+// It is equals to parent.Merge(s)
+func (s MixStyle) Inherit(parent MixStyle) MixStyle { return parent.Merge(s) }
+
+// Equal check the style is same as the other. the options are compared by the effect, not the order.
+func (s MixStyle) Equal(other MixStyle) bool {
+	return s.Fg == other.Fg && s.Bg == other.Bg && s.Ul == other.Ul && s.attrs() == other.attrs()
+}
+
+// Diff get the minimal color code to switch from the current style to the other.
+// returns empty string if they are equal, returns "0" if the other is empty.
+// The 256 and RGB colors are converted by the color level of the std renderer.
+//
+// eg: {Fg: red, bold}.Diff({Fg: red, italic}) -> "22;3"
+func (s MixStyle) Diff(to MixStyle) string {
+	if s.Equal(to) {
+		return ""
+	}
+	if to.IsEmpty() {
+		return "0"
+	}
+
+	dst := make([]byte, 0, 32)
+	if s.Fg != to.Fg {
+		dst = appendColorOrReset(dst, to.Fg, AsFg, "39")
+	}
+	if s.Bg != to.Bg {
+		dst = appendColorOrReset(dst, to.Bg, AsBg, "49")
+	}
+	if s.Ul != to.Ul {
+		dst = appendColorOrReset(dst, to.Ul, asUl, "59")
+	}
+
+	a, b := s.attrs(), to.attrs()
+	if a.bold && !b.bold || a.dim && !b.dim {
+		// 22 turn off both the bold and dim
+		dst = append(appendCodeSep(dst, 0), "22"...)
+		a.bold, a.dim = false, false
+	}
+	return ConvertCodeByLevel(string(a.appendDiff(dst, b)), std.level)
+}
+
+// append the color code, or the reset code if the color is empty
+func appendColorOrReset(dst []byte, c MixColor, role uint8, reset string) []byte {
+	dst = appendCodeSep(dst, 0)
+	if c.IsEmpty() {
+		return append(dst, reset...)
+	}
+	return c.appendCode(dst, role, LevelRgb)
+}
+
+// IsEmpty the style has no color and option
+func (s MixStyle) IsEmpty() bool {
+	return s.Fg.IsEmpty() && s.Bg.IsEmpty() && s.Ul.IsEmpty() && s.attrs() == mixAttrs{}
+}
+
+// ToMix returns self, for implements the Styler
+func (s MixStyle) ToMix() MixStyle { return s }
+
+// Print message
+func (s MixStyle) Print(a ...any) {
+	doPrintV2(s.String(), fmt.Sprint(a...))
+}
+
+// Printf format and print message
+func (s MixStyle) Printf(format string, a ...any) {
+	doPrintV2(s.String(), fmt.Sprintf(format, a...))
+}
+
+// Println print message with newline
+func (s MixStyle) Println(a ...any) {
+	doPrintlnV2(s.String(), a)
+}
+
+// Sprint returns rendered message
+func (s MixStyle) Sprint(a ...any) string { return RenderCode(s.String(), a...) }
+
+// Sprintf returns format and rendered message
+func (s MixStyle) Sprintf(format string, a ...any) string {
+	return RenderString(s.String(), fmt.Sprintf(format, a...))
+}
+
+// Code convert to color code string
+func (s MixStyle) Code() string { return s.String() }
+
+// String convert to color code string. eg: "38;2;255;153;0;44;1"
+func (s MixStyle) String() string {
+	return string(s.appendCode(make([]byte, 0, 48), LevelRgb))
+}
+
+// AppendRender append the rendered str to dst and returns the extended buffer.
+// It will not allocate memory if the dst has enough capacity.
+func (s MixStyle) AppendRender(dst []byte, str string) []byte {
+	dst, start, ok := std.appendStart(dst, str)
+	if !ok {
+		return dst
+	}
+	return appendRenderEnd(s.appendCode(dst, std.level), start, str)
+}
+
+// append the style code for the color level to dst. eg: "38;2;255;153;0;44;1"
+func (s MixStyle) appendCode(dst []byte, level Level) []byte {
+	start := len(dst)
+	if !s.Fg.IsEmpty() {
+		dst = s.Fg.appendCode(dst, AsFg, level)
+	}
+	if !s.Bg.IsEmpty() {
+		dst = s.Bg.appendCode(appendCodeSep(dst, start), AsBg, level)
+	}
+	if !s.Ul.IsEmpty() && level != Level16 {
+		dst = s.Ul.appendCode(appendCodeSep(dst, start), asUl, level)
+	}
+
+	// write the normalized options, the resets and off codes are applied. same as Equal, IsEmpty
+	if s.Opts.IsValid() || s.UlStyle.IsValid() {
+		var buf [9]Color
+		a := s.attrs()
+		dst = a.appendOpts(buf[:0], mixAttrs{}).appendCode(dst, start)
+		if a.ulStyle != 0 {
+			dst = append(appendCodeSep(dst, start), a.ulStyle.levelCode(level)...)
+		}
+	}
+	return dst
+}
+
+/*************************************************************
+ * convert the style types to MixStyle
+ *************************************************************/
+
+// ToMix convert to the MixStyle. the later fg or bg color will override the earlier one.
+func (s Style) ToMix() MixStyle {
+	var ms MixStyle
+	for _, c := range s {
+		switch {
+		case c.IsFg():
+			ms.Fg = Mix16(c)
+		case c.IsBg():
+			ms.Bg = Mix16(c)
+		case c == FgDefault:
+			ms.Fg = MixColor{}
+		case c == BgDefault:
+			ms.Bg = MixColor{}
+		default:
+			ms.Opts.Add(c)
+		}
+	}
+	return ms
+}
+
+// WithUlStyle convert to the MixStyle with the underline style.
+//
+// Usage:
+//
+//	color.Style{color.FgRed}.WithUlStyle(color.UnderlineCurly).Println("message")
+func (s Style) WithUlStyle(ulStyle UnderlineStyle) MixStyle {
+	ms := s.ToMix()
+	ms.UlStyle = ulStyle
+	return ms
+}
+
+// ToMix convert to the MixStyle
+func (s *Style256) ToMix() MixStyle {
+	ms := MixStyle{Opts: append(Opts(nil), s.opts...), UlStyle: s.ulStyle}
+	if s.fg[1] > 0 {
+		ms.Fg = Mix256(s.fg[0])
+	}
+	if s.bg[1] > 0 {
+		ms.Bg = Mix256(s.bg[0])
+	}
+	if s.ul[1] > 0 {
+		ms.Ul = Mix256(s.ul[0])
+	}
+	return ms
+}
+
+// ToMix convert to the MixStyle
+func (s *RGBStyle) ToMix() MixStyle {
+	ms := MixStyle{Opts: append(Opts(nil), s.opts...), UlStyle: s.ulStyle}
+	if s.fg[3] == 1 {
+		ms.Fg = MixRGB(s.fg[0], s.fg[1], s.fg[2])
+	}
+	if s.bg[3] == 1 {
+		ms.Bg = MixRGB(s.bg[0], s.bg[1], s.bg[2])
+	}
+	if s.ul[3] == 1 {
+		ms.Ul = MixRGB(s.ul[0], s.ul[1], s.ul[2])
+	}
+	return ms
+}
+
+/*************************************************************
+ * the attributes state of the options
+ *************************************************************/
+
+// mixAttrs the attributes state after apply the options in order. it is comparable.
+type mixAttrs struct {
+	bold, dim, italic, reverse, concealed, strike, overline bool
+	// underline: 0 - not set, OpUnderscore or OpDoubleUnderline
+	underline Color
+	// ulStyle the underline style, it is exclusive with the underline option
+	ulStyle UnderlineStyle
+	// blink: 0 - not set, OpBlink or OpFastBlink
+	blink Color
+}
+
+// mixAttrsOf apply the options list in order, get the attributes state.
+func mixAttrsOf(optsList ...Opts) (a mixAttrs) {
+	for _, opts := range optsList {
+		for _, c := range opts {
+			a.apply(c)
+		}
+	}
+	return
+}
+
+// get the attributes state of the style, the UlStyle is applied after the Opts.
+func (s MixStyle) attrs() mixAttrs {
+	a := mixAttrsOf(s.Opts)
+	a.applyUlStyle(s.UlStyle)
+	return a
+}
+
+// apply the options and the underline style in order.
+func (a *mixAttrs) applyAll(opts Opts, ulStyle UnderlineStyle) {
+	for _, c := range opts {
+		a.apply(c)
+	}
+	a.applyUlStyle(ulStyle)
+}
+
+// apply the underline style, UnderlineNone turns off the underline.
+func (a *mixAttrs) applyUlStyle(u UnderlineStyle) {
+	switch {
+	case u == UnderlineNone:
+		a.underline, a.ulStyle = 0, 0
+	case u.IsValid():
+		a.underline, a.ulStyle = 0, u
+	}
+}
+
+func (a *mixAttrs) apply(c Color) {
+	switch c {
+	case OpReset:
+		*a = mixAttrs{}
+	case OpBold:
+		a.bold = true
+	case OpFuzzy:
+		a.dim = true
+	case OpItalic:
+		a.italic = true
+	case OpUnderscore, OpDoubleUnderline:
+		a.underline, a.ulStyle = c, 0
+	case OpBlink, OpFastBlink:
+		a.blink = c
+	case OpReverse:
+		a.reverse = true
+	case OpConcealed:
+		a.concealed = true
+	case OpStrikethrough:
+		a.strike = true
+	case OpOverline:
+		a.overline = true
+	case 22:
+		a.bold, a.dim = false, false
+	case 23:
+		a.italic = false
+	case 24:
+		a.underline, a.ulStyle = 0, 0
+	case 25:
+		a.blink = 0
+	case 27:
+		a.reverse = false
+	case 28:
+		a.concealed = false
+	case 29:
+		a.strike = false
+	case 55:
+		a.overline = false
+	}
+}
+
+// opts convert the state to options list
+func (a mixAttrs) opts() Opts {
+	return a.appendOpts(nil, mixAttrs{})
+}
+
+// appendOpts append the on options which are not on in the old state.
+func (a mixAttrs) appendOpts(o Opts, old mixAttrs) Opts {
+	flags := [...]struct {
+		on, was bool
+		op      Color
+	}{
+		{a.bold, old.bold, OpBold},
+		{a.dim, old.dim, OpFuzzy},
+		{a.italic, old.italic, OpItalic},
+		{a.underline != 0, a.underline == old.underline, a.underline},
+		{a.blink != 0, a.blink == old.blink, a.blink},
+		{a.reverse, old.reverse, OpReverse},
+		{a.concealed, old.concealed, OpConcealed},
+		{a.strike, old.strike, OpStrikethrough},
+		{a.overline, old.overline, OpOverline},
+	}
+
+	for _, f := range flags {
+		if f.on && !f.was {
+			o = append(o, f.op)
+		}
+	}
+	return o
+}
+
+// appendDiff append the codes to dst for switch the state from a to b.
+func (a mixAttrs) appendDiff(dst []byte, b mixAttrs) []byte {
+	offs := [...]struct {
+		off  bool
+		code string
+	}{
+		{a.italic && !b.italic, "23"},
+		{(a.underline != 0 || a.ulStyle != 0) && b.underline == 0 && b.ulStyle == 0, "24"},
+		{a.blink != 0 && b.blink == 0, "25"},
+		{a.reverse && !b.reverse, "27"},
+		{a.concealed && !b.concealed, "28"},
+		{a.strike && !b.strike, "29"},
+		{a.overline && !b.overline, "55"},
+	}
+
+	for _, f := range offs {
+		if f.off {
+			dst = append(appendCodeSep(dst, 0), f.code...)
+		}
+	}
+
+	for _, c := range b.appendOpts(nil, a) {
+		dst = append(appendCodeSep(dst, 0), uint8Codes[c]...)
+	}
+	if b.ulStyle != 0 && b.ulStyle != a.ulStyle {
+		dst = append(appendCodeSep(dst, 0), b.ulStyle.Code()...)
+	}
+	return dst
+}
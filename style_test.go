@@ -100,6 +100,191 @@ func TestStyle(t *testing.T) {
 	delete(Styles, "new1")
 }
 
+func TestStyle_AppendRender(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	s := Style{FgGreen, BgBlack, OpBold}
+	is.Eq("32;40;1", s.String())
+	is.Eq(s.Render("msg"), string(s.AppendRender(nil, "msg")))
+	is.Eq("msg", string(Style{}.AppendRender(nil, "msg")))
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(50, func() {
+		buf = s.AppendRender(buf[:0], "message")
+	})
+	is.Eq(float64(0), allocs)
+}
+
+func TestMixStyle(t *testing.T) {
+	is := assert.New(t)
+
+	s := MixStyle{}
+	is.True(s.IsEmpty())
+	is.Eq("", s.Code())
+
+	// mix the RGB fg, 16 bg and 256 underline color
+	s = NewMix(MixHex("ff9900"), Mix16(BgBlue), OpBold, FgRed)
+	s.Ul, s.UlStyle = Mix256(196), UnderlineCurly
+	is.False(s.IsEmpty())
+	is.Eq(KindRGB, s.Fg.Kind())
+	is.Eq(Kind16, s.Bg.Kind())
+	is.Eq("38;2;255;153;0;44;58;5;196;1;4:3", s.Code())
+	is.Eq("38;5;208;44;58;5;196;1;4:3", string(s.appendCode(nil, Level256)))
+	is.Eq("93;44;1;4", string(s.appendCode(nil, Level16)))
+
+	// 16 and RGB underline color
+	is.Eq("58;5;9", MixStyle{Ul: Mix16(FgLightRed)}.Code())
+	is.Eq("58;2;1;2;3", MixStyle{Ul: MixRGB(1, 2, 3)}.Code())
+	is.Eq("58;5;0", string(MixStyle{Ul: MixRGB(1, 2, 3)}.appendCode(nil, Level256)))
+
+	// invalid color
+	is.True(Mix16(OpBold).IsEmpty())
+	is.True(MixHex("invalid").IsEmpty())
+
+	r, g, b := Mix256(232).RGB()
+	is.Eq([3]uint8{8, 8, 8}, [3]uint8{r, g, b})
+	r, g, b = MixRGB(1, 2, 3).RGB()
+	is.Eq([3]uint8{1, 2, 3}, [3]uint8{r, g, b})
+
+	// render
+	forceOpenColorRender()
+	defer resetColorRender()
+
+	s = MixStyle{Fg: Mix256(132), Bg: Mix16(BgBlack)}
+	is.Eq("\x1b[38;5;132;40mmsg\x1b[0m", s.Sprint("msg"))
+	is.Eq("\x1b[38;5;132;40mmsg\x1b[0m", s.Sprintf("m%s", "sg"))
+	is.Eq(s.Sprint("msg"), string(s.AppendRender(nil, "msg")))
+	is.Eq("msg", string(MixStyle{}.AppendRender(nil, "msg")))
+}
+
+func TestMixStyle_Merge(t *testing.T) {
+	is := assert.New(t)
+
+	base := MixStyle{Fg: Mix16(FgRed), Bg: Mix256(234), Opts: Opts{OpBold, OpItalic}}
+	s := base.Merge(MixStyle{Fg: MixHex("00f"), Opts: Opts{22}, UlStyle: UnderlineCurly})
+	is.Eq(MixHex("00f"), s.Fg)
+	is.Eq(Mix256(234), s.Bg)
+	is.Eq(Opts{OpItalic}, s.Opts)
+	is.Eq(UnderlineCurly, s.UlStyle)
+	// base is not changed
+	is.Eq(Opts{OpBold, OpItalic}, base.Opts)
+
+	// inherit the unset values from parent
+	child := MixStyle{Bg: Mix16(BgCyan), Opts: Opts{OpStrikethrough}}
+	s = child.Inherit(base)
+	is.Eq(Mix16(FgRed), s.Fg)
+	is.Eq(Mix16(BgCyan), s.Bg)
+	is.Eq("31;46;1;3;9", s.Code())
+	is.True(s.Equal(base.Merge(child)))
+
+	// reset option in the other style
+	s = base.Merge(MixStyle{Opts: Opts{OpReset, OpReverse}})
+	is.Eq(Opts{OpReverse}, s.Opts)
+
+	// the underline option and style override each other
+	s = MixStyle{UlStyle: UnderlineCurly}.Merge(MixStyle{Opts: Opts{OpUnderscore}})
+	is.Eq("4", s.Code())
+	is.Eq(UnderlineStyle(0), s.UlStyle)
+	s = MixStyle{UlStyle: UnderlineCurly}.Merge(MixStyle{Opts: Opts{24}})
+	is.True(s.IsEmpty())
+	s = MixStyle{Opts: Opts{OpUnderscore}}.Merge(MixStyle{UlStyle: UnderlineNone})
+	is.True(s.IsEmpty())
+}
+
+func TestMixStyle_Equal(t *testing.T) {
+	is := assert.New(t)
+
+	a := MixStyle{Fg: Mix16(FgRed), Opts: Opts{OpBold, OpItalic}}
+	is.True(a.Equal(MixStyle{Fg: Mix16(FgRed), Opts: Opts{OpItalic, OpBold, OpBold}}))
+	is.True(a.Equal(MixStyle{Fg: Mix16(FgRed), Opts: Opts{OpUnderscore, OpItalic, OpBold, 24}}))
+	is.False(a.Equal(MixStyle{Fg: Mix16(FgRed), Opts: Opts{OpBold}}))
+	is.False(a.Equal(MixStyle{Fg: Mix256(1), Opts: Opts{OpBold, OpItalic}}))
+	is.True(MixStyle{Opts: Opts{OpBold, 22}}.IsEmpty())
+
+	// output the normalized options, same as the Equal and IsEmpty
+	red := Mix16(FgRed)
+	s := MixStyle{Fg: red, Opts: Opts{OpBold, OpReset, OpItalic}}
+	is.Eq("31;3", s.Code())
+	is.True(s.Equal(MixStyle{Fg: red, Opts: Opts{OpItalic}}))
+	s = MixStyle{Opts: Opts{OpBold, OpReset}}
+	is.True(s.IsEmpty())
+	is.Eq("", s.Code())
+	is.Eq("1;4:3", MixStyle{Opts: Opts{OpUnderscore, OpBold, OpBold}, UlStyle: UnderlineCurly}.Code())
+	is.True(MixStyle{}.Equal(MixStyle{UlStyle: UnderlineNone}))
+	is.False(MixStyle{Opts: Opts{OpUnderscore}}.Equal(MixStyle{Opts: Opts{OpUnderscore}, UlStyle: UnderlineCurly}))
+}
+
+func TestMixStyle_Diff(t *testing.T) {
+	is := assert.New(t)
+
+	red := Mix16(FgRed)
+	tests := []struct {
+		from, to MixStyle
+		want     string
+	}{
+		{MixStyle{}, MixStyle{}, ""},
+		{MixStyle{Fg: red}, MixStyle{}, "0"},
+		{MixStyle{}, MixStyle{Fg: red, Opts: Opts{OpBold}}, "31;1"},
+		{MixStyle{Fg: red, Opts: Opts{OpBold}}, MixStyle{Fg: red, Opts: Opts{OpItalic}}, "22;3"},
+		{MixStyle{Fg: red, Opts: Opts{OpBold, OpFuzzy}}, MixStyle{Fg: red, Opts: Opts{OpFuzzy}}, "22;2"},
+		{MixStyle{Fg: red, Bg: Mix256(23)}, MixStyle{Bg: Mix256(23), Opts: Opts{OpBold}}, "39;1"},
+		{MixStyle{Bg: Mix256(23)}, MixStyle{Fg: red, Bg: MixRGB(1, 2, 3)}, "31;48;2;1;2;3"},
+		{MixStyle{Ul: red, UlStyle: UnderlineCurly}, MixStyle{Ul: red, UlStyle: UnderlineDashed}, "4:5"},
+		{MixStyle{UlStyle: UnderlineCurly}, MixStyle{Opts: Opts{OpBold}}, "24;1"},
+		{MixStyle{Opts: Opts{OpUnderscore}}, MixStyle{UlStyle: UnderlineCurly}, "4:3"},
+		{MixStyle{Fg: red, Ul: red, Opts: Opts{OpUnderscore}}, MixStyle{Fg: red}, "59;24"},
+		{
+			MixStyle{Fg: red, Opts: Opts{OpItalic, OpBlink, OpReverse, OpConcealed, OpStrikethrough, OpOverline}},
+			MixStyle{Fg: red, Opts: Opts{OpFastBlink}},
+			"23;27;28;29;55;6",
+		},
+	}
+
+	for _, tt := range tests {
+		is.Eq(tt.want, tt.from.Diff(tt.to))
+	}
+
+	// convert by the color level
+	forceOpenColorRender()
+	defer resetColorRender()
+	ForceSetColorLevel(Level256)
+	is.Eq("38;5;0;48;5;196", MixStyle{}.Diff(MixStyle{Fg: MixRGB(1, 2, 3), Bg: Mix256(196)}))
+	ForceSetColorLevel(Level16)
+	is.Eq("31;101", MixStyle{}.Diff(MixStyle{Fg: MixRGB(170, 0, 0), Bg: Mix256(196)}))
+	is.Eq("39", MixStyle{Fg: red}.Diff(MixStyle{Ul: MixRGB(1, 2, 3)}))
+}
+
+func TestStyler_ToMix(t *testing.T) {
+	is := assert.New(t)
+
+	ms := Style{FgRed, BgBlue, OpBold, FgGreen, OpOverline, Color(200)}.ToMix()
+	is.Eq(Mix16(FgGreen), ms.Fg)
+	is.Eq(Mix16(BgBlue), ms.Bg)
+	is.Eq(Opts{OpBold, OpOverline}, ms.Opts)
+	is.True(Style{FgRed, FgDefault}.ToMix().IsEmpty())
+
+	s256 := S256(132, 203).SetUl(196).SetUlStyle(UnderlineCurly)
+	ms = s256.ToMix()
+	is.Eq(s256.Code(), ms.Code())
+	is.Eq(Kind256, ms.Ul.Kind())
+	is.Eq(UnderlineCurly, ms.UlStyle)
+
+	rs := HEXStyle("eee", "333").SetUl(HEX("f00")).AddOpts(OpBold)
+	ms = rs.ToMix()
+	is.Eq(rs.Code(), ms.Code())
+
+	// mix the converted styles
+	ms = Style{BgBlue, OpItalic}.ToMix().Merge(rs.ToMix())
+	is.Eq("38;2;238;238;238;48;2;51;51;51;58;2;255;0;0;1;3", ms.Code())
+
+	stylers := []Styler{Style{FgRed}, S256(132), HEXStyle("eee"), MixStyle{Fg: Mix16(FgRed)}}
+	for _, s := range stylers {
+		is.Eq(s.Code(), s.ToMix().Code())
+	}
+}
+
 func TestThemes(t *testing.T) {
 	// force open color render for testing
 	buf := forceOpenColorRender()
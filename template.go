@@ -0,0 +1,206 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Template a compiled color tag template. see Compile()
+//
+// The color tags are parsed once on compile, the rendered format strings are
+// precomputed for each color level. On execution, it will select the format by
+// the current Enable and color level setting of the renderer, then call fmt.
+//
+// The template has tag functions, links or verbs in the tags, or the args contain
+// tags or resets, will fall back to Sprintf: format the template, then render the color tags.
+type Template struct {
+	rd  *Renderer
+	raw string
+	// dynamic the template has tag functions or links, cannot precompute.
+	dynamic bool
+	// formats rendered format string for each color level. index is the Level
+	formats [LevelRgb + 1]string
+	// resets the number of ResetSet in the formats. if more in the output, the args contain resets
+	resets [LevelRgb + 1]int
+	// tags, slashes the number of "<" and "\" in the formats. if more in the output,
+	// the args contain tags or backslashes may escape the tags.
+	tags, slashes int
+}
+
+// a text part of the template, with the color code of the tags enclosing it.
+type tplPart struct {
+	text string
+	code string
+}
+
+// Compile parse the color tags in the template and returns a Template
+// for reuse on hot paths. The output is same as Sprintf(template, args...)
+//
+// Like Parse(), the unknown and unclosed tags are kept as literal text, so the error is nil now.
+// Use ValidateTag() to check the template strictly.
+//
+// Usage:
+//
+//	tpl := color.MustCompile("<info>%s</> done in <cyan>%dms</>")
+//	tpl.Printf("build", 23)
+//	s := tpl.Sprintf("test", 56)
+func Compile(template string) (*Template, error) { return std.Compile(template) }
+
+// MustCompile like Compile, but will panic on error.
+func MustCompile(template string) *Template {
+	t, err := Compile(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Compile the color tag template by the renderer settings. see Compile()
+func (r *Renderer) Compile(template string) (*Template, error) {
+	t := &Template{rd: r, raw: template}
+	parts, dynamic := r.parser.compileParts(template)
+	if dynamic {
+		t.dynamic = true
+		return t, nil
+	}
+
+	for level := range t.formats {
+		t.formats[level] = buildTplFormat(parts, Level(level))
+		t.resets[level] = strings.Count(t.formats[level], ResetSet)
+	}
+	t.tags = strings.Count(t.formats[LevelNo], "<")
+	t.slashes = strings.Count(t.formats[LevelNo], `\`)
+	return t, nil
+}
+
+// compile the template to text parts. dynamic is true if the template has tag functions, links
+// or verbs in the tags. eg: "<fg=%s>"
+func (tp *TagParser) compileParts(str string) (parts []tplPart, dynamic bool) {
+	lx := newTagLexer(str)
+	for tok, ok := lx.next(); ok; tok, ok = lx.next() {
+		if tok.kind != tagText && strings.IndexByte(tok.name, '%') >= 0 {
+			return nil, true
+		}
+	}
+
+	hasEscape := strings.Contains(str, `\<`)
+	toks := tp.pairTags(str)
+
+	// style stack, item is the color code of the open tag
+	var codes []string
+	var last int
+	for _, tok := range toks {
+		if tok.fn != nil || tok.url != "" {
+			return nil, true
+		}
+
+		text := str[last:tok.start]
+		if hasEscape {
+			text = unescapeTag(text, true)
+		}
+		parts = append(parts, tplPart{text: text, code: strings.Join(codes, ";")})
+		last = tok.end
+
+		if tok.kind == tagOpen {
+			codes = append(codes, tok.code)
+		} else {
+			codes = codes[:len(codes)-tok.closes]
+		}
+	}
+
+	text := str[last:]
+	if hasEscape {
+		text = unescapeTag(text, false)
+	}
+	return append(parts, tplPart{text: text}), false
+}
+
+// build the format string for the color level. LevelNo will output plain text.
+func buildTplFormat(parts []tplPart, level Level) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		if p.text == "" {
+			continue
+		}
+
+		if p.code == "" || level == LevelNo {
+			sb.WriteString(p.text)
+			continue
+		}
+
+		sb.WriteString(StartSet)
+		sb.WriteString(ConvertCodeByLevel(p.code, level))
+		sb.WriteByte('m')
+		sb.WriteString(p.text)
+		sb.WriteString(ResetSet)
+	}
+	return sb.String()
+}
+
+// String get the raw template string
+func (t *Template) String() string { return t.raw }
+
+// format the template with args for output to the writer. w is nil for returns string.
+func (t *Template) sprintf(w io.Writer, a []any) string {
+	if !*t.rd.renderTag || t.dynamic {
+		return t.fallback(w, a)
+	}
+
+	// strip color codes for the writer, same as Fprintf
+	if w != nil && !t.rd.canRenderTo(w) {
+		if s := fmt.Sprintf(t.formats[LevelNo], a...); !t.hasArgTags(s) {
+			return ClearCode(s)
+		}
+		return t.fallback(w, a)
+	}
+
+	level := t.rd.level
+	if !t.rd.canRender() {
+		level = LevelNo
+	} else if level > LevelRgb {
+		level = LevelRgb
+	}
+
+	s := fmt.Sprintf(t.formats[level], a...)
+	if t.hasArgTags(s) || level != LevelNo && strings.Count(s, ResetSet) != t.resets[level] {
+		// the args contain tags or resets, render them like Sprintf
+		return t.fallback(w, a)
+	}
+	return s
+}
+
+// check the args contain tags or backslashes, by compare the formatted string with the format
+func (t *Template) hasArgTags(s string) bool {
+	return strings.Count(s, "<") != t.tags || strings.Count(s, `\`) != t.slashes
+}
+
+// format the raw template and render the color tags, same as Sprintf and Fprintf
+func (t *Template) fallback(w io.Writer, a []any) string {
+	s := fmt.Sprintf(t.raw, a...)
+	if w == nil {
+		return t.rd.ReplaceTag(s)
+	}
+	return t.rd.replaceTagTo(w, s)
+}
+
+// Sprintf format the template with args, returns rendered string.
+func (t *Template) Sprintf(a ...any) string { return t.sprintf(nil, a) }
+
+// Printf format the template with args and print to the renderer output.
+func (t *Template) Printf(a ...any) {
+	t.Fprintf(t.rd.output, a...)
+}
+
+// Fprintf format the template with args and print to the writer.
+//
+// Notice: will ignore print error
+func (t *Template) Fprintf(w io.Writer, a ...any) {
+	saveInternalError(t.Execute(w, a...))
+}
+
+// Execute format the template with args and write to the writer, returns the write error.
+func (t *Template) Execute(w io.Writer, a ...any) error {
+	_, err := io.WriteString(w, t.sprintf(w, a))
+	return err
+}
@@ -0,0 +1,137 @@
+package color
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestCompile(t *testing.T) {
+	buf := forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	tpl, err := Compile("<info>%s</> done in <fg=fc1cac>%d<op=bold>ms</></>, 100%%")
+	is.NoErr(err)
+	is.Eq("<info>%s</> done in <fg=fc1cac>%d<op=bold>ms</></>, 100%%", tpl.String())
+
+	// same as Sprintf
+	want := Sprintf(tpl.String(), "build", 23)
+	is.Eq(want, tpl.Sprintf("build", 23))
+	is.Eq("\x1b[0;32mbuild\x1b[0m done in \x1b[38;2;252;28;172m23\x1b[0m\x1b[38;2;252;28;172;1mms\x1b[0m, 100%", want)
+
+	tpl.Printf("test", 5)
+	is.Eq("\x1b[0;32mtest\x1b[0m done in \x1b[38;2;252;28;172m5\x1b[0m\x1b[38;2;252;28;172;1mms\x1b[0m, 100%", buf.String())
+
+	// the tags in args are parsed, same as Sprintf
+	tpl = MustCompile(`<red>%s</> \<T>`)
+	is.Eq(Sprintf(tpl.String(), "<info>a</>"), tpl.Sprintf("<info>a</>"))
+	is.Eq("\x1b[0;31;32ma\x1b[0m <T>", tpl.Sprintf("<info>a</>"))
+	is.Eq(Sprintf(tpl.String(), `a\`), tpl.Sprintf(`a\`))
+	tpl = MustCompile("%s<info>b</>")
+	is.Eq(Sprintf(tpl.String(), `a\`), tpl.Sprintf(`a\`))
+	is.Eq("a<info>b</>", ClearCode(tpl.Sprintf(`a\`)))
+
+	// re-apply the color after the colored arg, same as Sprintf
+	tpl = MustCompile("<info>%s done</> %d%% <red>%5.1f</>")
+	arg := FgCyan.Sprint("a")
+	want = Sprintf(tpl.String(), arg, 2, 1.5)
+	is.Eq(want, tpl.Sprintf(arg, 2, 1.5))
+	is.Eq("\x1b[0;32m\x1b[36ma\x1b[0m\x1b[0;32m done\x1b[0m 2% \x1b[0;31m  1.5\x1b[0m", want)
+	buf.Reset()
+	tpl.Printf(arg, 2, 1.5)
+	is.Eq(want, buf.String())
+
+	// nested tag builds on the enclosing style
+	tpl = MustCompile("<bold>%s <red>b</></>")
+	is.Eq("\x1b[1ma \x1b[0m\x1b[1;31mb\x1b[0m", tpl.Sprintf("a"))
+
+	// cannot split the args by the parts, output as fmt
+	tpl = MustCompile("<info>%[1]s</>")
+	is.Eq(Sprintf(tpl.String(), arg), tpl.Sprintf(arg))
+
+	// the verb in the tag, will parse on execution
+	tpl = MustCompile("<fg=%s>%d</>")
+	is.Eq("\x1b[31m2\x1b[0m", tpl.Sprintf("red", 2))
+
+	// the unknown and unclosed tags are kept as literal text, same as Sprintf
+	tpl, err = Compile("List<T> <info>%s</> <unknown>a</> <red>b")
+	is.NoErr(err)
+	is.Eq(Sprintf(tpl.String(), "c"), tpl.Sprintf("c"))
+	is.Eq("List<T> \x1b[0;32mc\x1b[0m <unknown>a</> <red>b", tpl.Sprintf("c"))
+}
+
+func TestTemplate_level(t *testing.T) {
+	forceOpenColorRender()
+	defer resetColorRender()
+	is := assert.New(t)
+
+	tpl := MustCompile("<fg=fc1cac>%s</>")
+
+	// honors the level setting on execution
+	ForceSetColorLevel(Level256)
+	is.Eq("\x1b[38;5;199mmsg\x1b[0m", tpl.Sprintf("msg"))
+	ForceSetColorLevel(Level16)
+	is.Eq("\x1b[35mmsg\x1b[0m", tpl.Sprintf("msg"))
+	// unknown level, use the RGB format
+	ForceSetColorLevel(LevelRgb + 1)
+	is.Eq("\x1b[38;2;252;28;172mmsg\x1b[0m", tpl.Sprintf("msg"))
+
+	Disable()
+	is.Eq("msg", tpl.Sprintf("msg"))
+	Enable = true
+
+	RenderTag = false
+	is.Eq("<fg=fc1cac>msg</>", tpl.Sprintf("msg"))
+	RenderTag = true
+}
+
+func TestRenderer_Compile(t *testing.T) {
+	is := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	r := NewRenderer(buf)
+	r.SetLevel(Level16)
+	r.TagParser().RegisterTag("title", "1;36")
+
+	tpl, err := r.Compile("<title>%s</>")
+	is.NoErr(err)
+	is.NoErr(tpl.Execute(buf, "a"))
+	is.Eq("\x1b[1;36ma\x1b[0m", buf.String())
+
+	// not render color to the non-terminal writer
+	r.SetDetectWriter(true)
+	buf.Reset()
+	tpl.Fprintf(buf, "a")
+	is.Eq("a", buf.String())
+
+	// the color codes in args are stripped, same as Fprintf
+	buf.Reset()
+	is.NoErr(tpl.Execute(buf, "\x1b[31mx\x1b[0m"))
+	is.Eq("x", buf.String())
+	buf.Reset()
+	r.Fprintf(buf, "<title>%s</>", "\x1b[31mx\x1b[0m")
+	is.Eq("x", buf.String())
+
+	// has tag function, will parse on execution
+	r.SetDetectWriter(false)
+	r.TagParser().RegisterTagFunc("upper", func(body string, _ map[string]string) string {
+		return strings.ToUpper(body)
+	})
+	tpl, err = r.Compile("<upper>%s</> <title>%d</>")
+	is.NoErr(err)
+	is.Eq("ABC \x1b[1;36m1\x1b[0m", tpl.Sprintf("abc", 1))
+
+	// same as Sprintf, the tags in args are parsed
+	is.Eq(r.Sprintf(tpl.String(), "<red>a</>", 1), tpl.Sprintf("<red>a</>", 1))
+	is.Eq("A \x1b[1;36m1\x1b[0m", tpl.Sprintf("<red>a</>", 1))
+	is.Eq(r.Sprintf(tpl.String(), "a"), tpl.Sprintf("a"))
+
+	// output to the non-terminal writer, same as Fprintf
+	r.SetDetectWriter(true)
+	buf.Reset()
+	tpl.Fprintf(buf, "<red>a</>", 2)
+	is.Eq("A 2", buf.String())
+}
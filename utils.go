@@ -8,73 +8,40 @@ import (
 )
 
 // SetTerminal by given code.
-func SetTerminal(code string) error {
-	if !Enable || !SupportColor() {
-		return nil
-	}
-
-	_, err := fmt.Fprintf(output, SettingTpl, code)
-	return err
-}
+func SetTerminal(code string) error { return std.SetTerminal(code) }
 
 // ResetTerminal terminal setting.
-func ResetTerminal() error {
-	if !Enable || !SupportColor() {
-		return nil
-	}
-
-	_, err := fmt.Fprint(output, ResetSet)
-	return err
-}
+func ResetTerminal() error { return std.ResetTerminal() }
 
 /*************************************************************
  * print methods(will auto parse color tags)
  *************************************************************/
 
 // Print render color tag and print messages
-func Print(a ...any) {
-	Fprint(output, a...)
-}
+func Print(a ...any) { std.Print(a...) }
 
 // Printf format and print messages
-func Printf(format string, a ...any) {
-	Fprintf(output, format, a...)
-}
+func Printf(format string, a ...any) { std.Printf(format, a...) }
 
 // Println messages with new line
-func Println(a ...any) {
-	Fprintln(output, a...)
-}
+func Println(a ...any) { std.Println(a...) }
 
 // Fprint print rendered messages to writer
 //
 // Notice: will ignore print error
-func Fprint(w io.Writer, a ...any) {
-	_, err := fmt.Fprint(w, Render(a...))
-	saveInternalError(err)
-}
+func Fprint(w io.Writer, a ...any) { std.Fprint(w, a...) }
 
 // Fprintf print format and rendered messages to writer.
 // Notice: will ignore print error
-func Fprintf(w io.Writer, format string, a ...any) {
-	str := fmt.Sprintf(format, a...)
-	_, err := fmt.Fprint(w, ReplaceTag(str))
-	saveInternalError(err)
-}
+func Fprintf(w io.Writer, format string, a ...any) { std.Fprintf(w, format, a...) }
 
 // Fprintln print rendered messages line to writer
 // Notice: will ignore print error
-func Fprintln(w io.Writer, a ...any) {
-	str := formatLikePrintln(a)
-	_, err := fmt.Fprintln(w, ReplaceTag(str))
-	saveInternalError(err)
-}
+func Fprintln(w io.Writer, a ...any) { std.Fprintln(w, a...) }
 
 // Lprint passes colored messages to a log.Logger for printing.
 // Notice: should be goroutine safe
-func Lprint(l *log.Logger, a ...any) {
-	l.Print(Render(a...))
-}
+func Lprint(l *log.Logger, a ...any) { std.Lprint(l, a...) }
 
 // Render parse color tags, return rendered string.
 //
@@ -82,25 +49,13 @@ func Lprint(l *log.Logger, a ...any) {
 //
 //	text := Render("<info>hello</> <cyan>world</>!")
 //	fmt.Println(text)
-func Render(a ...any) string {
-	if len(a) == 0 {
-		return ""
-	}
-	return ReplaceTag(fmt.Sprint(a...))
-}
+func Render(a ...any) string { return std.Render(a...) }
 
 // Sprint parse color tags, return rendered string
-func Sprint(a ...any) string {
-	if len(a) == 0 {
-		return ""
-	}
-	return ReplaceTag(fmt.Sprint(a...))
-}
+func Sprint(a ...any) string { return std.Sprint(a...) }
 
 // Sprintf format and return rendered string
-func Sprintf(format string, a ...any) string {
-	return ReplaceTag(fmt.Sprintf(format, a...))
-}
+func Sprintf(format string, a ...any) string { return std.Sprintf(format, a...) }
 
 // String alias of the ReplaceTag
 func String(s string) string { return ReplaceTag(s) }
@@ -122,17 +77,10 @@ func Text(s string) string { return ReplaceTag(s) }
  *************************************************************/
 
 // new implementation, support render full color code on pwsh.exe, cmd.exe
-func doPrintV2(code, str string) {
-	_, err := fmt.Fprint(output, RenderString(code, str))
-	saveInternalError(err)
-}
+func doPrintV2(code, str string) { std.doPrint(code, str) }
 
 // new implementation, support render full color code on pwsh.exe, cmd.exe
-func doPrintlnV2(code string, args []any) {
-	str := formatLikePrintln(args)
-	_, err := fmt.Fprintln(output, RenderString(code, str))
-	saveInternalError(err)
-}
+func doPrintlnV2(code string, args []any) { std.doPrintln(code, args) }
 
 // use Println, will add spaces for each arg
 func formatLikePrintln(args []any) (message string) {
@@ -0,0 +1,391 @@
+package color
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gookit/color/ansi"
+)
+
+// the OSC 8 to close a hyperlink
+const linkCloseSeq = "\x1b]8;;\x1b\\"
+
+// wide char ranges of the East Asian Wide(W), Fullwidth(F) and emoji.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4}, {0x17000, 0x18AFF}, {0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA},
+	{0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440},
+	{0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A},
+	{0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6DC, 0x1F6DF}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC},
+	{0x1F7E0, 0x1F7EB}, {0x1F7F0, 0x1F7F0}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// runeWidth get the display width of the rune: 0, 1 or 2.
+//
+// The control chars, combining marks, format chars(eg: ZWJ) and emoji modifiers are zero width.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7F:
+		return 0
+	case r < 0x300:
+		return 1
+	case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && r >= wideRanges[i][0] {
+		return 2
+	}
+	return 1
+}
+
+// textWidth get the display width of the plain text. the rune after ZWJ is joined, zero width.
+func textWidth(s string) (width int) {
+	var joined bool
+	for _, r := range s {
+		if !joined {
+			width += runeWidth(r)
+		}
+		joined = r == 0x200D
+	}
+	return
+}
+
+// VisibleWidth get the display width of the string, the ANSI escape sequences are skipped.
+// The East Asian wide chars and emoji are counted as two columns.
+//
+// Usage:
+//
+//	color.VisibleWidth(color.Red.Sprint("hi")) // 2
+//	color.VisibleWidth("你好") // 4
+func VisibleWidth(s string) int {
+	if strings.IndexByte(s, ansi.ESC) < 0 {
+		return textWidth(s)
+	}
+
+	var width int
+	lx := ansi.NewLexer(s)
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		if tok.Kind == ansi.KindText {
+			width += textWidth(tok.Raw)
+		}
+	}
+	return width
+}
+
+// Truncate the string to the display width, the tail will be appended if truncated.
+// The width of the tail is counted in the width, returns empty string if width <= 0. see VisibleWidth()
+//
+// The escape sequences after the truncated position are dropped, and if the text is truncated
+// inside a style or hyperlink, the tail is output in it and then the style is reset, the link is closed.
+//
+// Usage:
+//
+//	s := color.Truncate(color.Green.Sprint("hello world"), 8, "...") // green "hello..."
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 {
+		return ""
+	}
+	if VisibleWidth(s) <= width {
+		return s
+	}
+
+	limit := width - VisibleWidth(tail)
+	// the tail is wider than the width, truncate the tail only
+	if limit < 0 {
+		s, tail, limit = tail, "", width
+	}
+
+	var sb strings.Builder
+	// the style and hyperlink is active at the truncated position
+	var styled, linked bool
+	var w int
+
+	lx := ansi.NewLexer(s)
+LOOP:
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		switch tok.Kind {
+		case ansi.KindText:
+			str := tok.Raw
+			var joined bool
+			for i, r := range str {
+				rw := runeWidth(r)
+				if joined {
+					rw = 0
+				}
+				if w+rw > limit {
+					sb.WriteString(str[:i])
+					break LOOP
+				}
+
+				w += rw
+				joined = r == 0x200D
+			}
+			sb.WriteString(str)
+			continue
+		case ansi.KindSGR:
+			styled = !isResetSGR(tok)
+		case ansi.KindOSC:
+			if tok.Cmd == 8 { // hyperlink: "8;params;URI"
+				i := strings.IndexByte(tok.Data, ';')
+				linked = i >= 0 && i+1 < len(tok.Data)
+			}
+		}
+		sb.WriteString(tok.Raw)
+	}
+
+	sb.WriteString(tail)
+	if styled {
+		sb.WriteString(ResetSet)
+	}
+	if linked {
+		sb.WriteString(linkCloseSeq)
+	}
+	return sb.String()
+}
+
+// check the SGR is reset all: "\x1b[0m", "\x1b[m"
+func isResetSGR(tok ansi.Token) bool {
+	for _, attr := range tok.SGR() {
+		if attr.Code != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PadRight pad the string with spaces on the right to the display width. see VisibleWidth()
+func PadRight(s string, width int) string {
+	if n := width - VisibleWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft pad the string with spaces on the left to the display width. see VisibleWidth()
+func PadLeft(s string, width int) string {
+	if n := width - VisibleWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center pad the string with spaces on both sides to the display width.
+// If the padding is odd, the right side will have one more space.
+func Center(s string, width int) string {
+	n := width - VisibleWidth(s)
+	if n <= 0 {
+		return s
+	}
+
+	left := n / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", n-left)
+}
+
+// WrapOptions for the Wrap()
+type WrapOptions struct {
+	// Prefix write before the first line. eg: "ERROR: "
+	Prefix string
+	// Indent for the continuation lines. if empty, will use spaces of the Prefix width for hanging indent.
+	Indent string
+}
+
+// a piece of the word, it is a char or an escape sequence.
+type wrapPiece struct {
+	raw string
+	// width of the char. 0 for escape sequence
+	width int
+	esc   bool
+}
+
+// wrapper state for the Wrap()
+type wrapper struct {
+	sb     strings.Builder
+	width  int
+	indent string
+	// available width of the current line
+	avail int
+	lineW int
+	// active SGR sequences and hyperlink on current position
+	styles []string
+	link   string
+	// the line is started by wrap, not a new paragraph
+	wrapped bool
+	// pending word and the spaces before it
+	word   []wrapPiece
+	wordW  int
+	spaces int
+}
+
+// Wrap the string on word boundaries by the display width. see VisibleWidth()
+//
+// The active styles and hyperlink are closed at each line end and re-opened on the next line.
+// The word longer than the width will be split. Newlines in the string are kept.
+// The spaces at the wrap points are removed, the spaces before the newlines and
+// at the end of the string are kept. eg: "ab  \ncd " -> "ab  \ncd "
+//
+// If the Prefix or Indent is not narrower than the width, the text after it is wrapped
+// by the whole width, so the line will be wider than the width.
+// eg: Wrap("abcdefghij", 4, WrapOptions{Prefix: "ERROR: "}) -> "ERROR: abcd\n       efgh\n       ij"
+//
+// Usage:
+//
+//	s := color.Wrap(color.Red.Sprint(msg), 40, color.WrapOptions{Prefix: "ERROR: "})
+func Wrap(s string, width int, opts WrapOptions) string {
+	if width <= 0 {
+		return opts.Prefix + s
+	}
+
+	w := &wrapper{width: width, indent: opts.Indent}
+	if w.indent == "" {
+		w.indent = strings.Repeat(" ", VisibleWidth(opts.Prefix))
+	}
+
+	w.sb.Grow(len(s) + len(opts.Prefix) + len(s)/width*(len(w.indent)+8))
+	w.sb.WriteString(opts.Prefix)
+	w.setAvail(opts.Prefix)
+
+	lx := ansi.NewLexer(s)
+	for tok, ok := lx.Next(); ok; tok, ok = lx.Next() {
+		if tok.Kind != ansi.KindText {
+			w.word = append(w.word, wrapPiece{raw: tok.Raw, esc: true})
+			continue
+		}
+
+		var joined bool
+		for i, r := range tok.Raw {
+			switch r {
+			case ' ':
+				w.flushWord()
+				w.spaces++
+			case '\n':
+				w.flushWord()
+				w.writeSpaces()
+				w.newLine(false)
+			default:
+				raw := tok.Raw[i : i+utf8.RuneLen(r)]
+				rw := runeWidth(r)
+				// zero width or joined rune, append to the last char
+				if n := len(w.word); n > 0 && !w.word[n-1].esc && (rw == 0 || joined) {
+					w.word[n-1].raw += raw
+				} else {
+					w.word = append(w.word, wrapPiece{raw: raw, width: rw})
+					w.wordW += rw
+				}
+			}
+			joined = r == 0x200D
+		}
+	}
+
+	w.flushWord()
+	w.writeSpaces()
+	return w.sb.String()
+}
+
+// write the pending spaces and word to the line, will wrap the line if no enough width.
+func (w *wrapper) flushWord() {
+	if len(w.word) == 0 {
+		return
+	}
+
+	if w.lineW > 0 && w.wordW > 0 && w.lineW+w.spaces+w.wordW > w.avail {
+		w.newLine(true)
+	} else {
+		w.writeSpaces()
+	}
+
+	for _, p := range w.word {
+		if p.esc {
+			w.sb.WriteString(p.raw)
+			w.applyEscape(p.raw)
+			continue
+		}
+
+		// split the long word
+		if w.lineW > 0 && w.lineW+p.width > w.avail {
+			w.newLine(true)
+		}
+		w.sb.WriteString(p.raw)
+		w.lineW += p.width
+	}
+
+	w.word = w.word[:0]
+	w.wordW, w.spaces = 0, 0
+}
+
+// write the pending spaces to the line. the spaces at the start of a wrapped line are removed.
+func (w *wrapper) writeSpaces() {
+	if w.lineW > 0 || !w.wrapped {
+		w.sb.WriteString(strings.Repeat(" ", w.spaces))
+		w.lineW += w.spaces
+	}
+	w.spaces = 0
+}
+
+// update the active styles and hyperlink by the escape sequence
+func (w *wrapper) applyEscape(raw string) {
+	tok, _ := ansi.NewLexer(raw).Next()
+	switch tok.Kind {
+	case ansi.KindSGR:
+		if isResetSGR(tok) {
+			w.styles = w.styles[:0]
+		} else {
+			w.styles = append(w.styles, raw)
+		}
+	case ansi.KindOSC:
+		if tok.Cmd == 8 {
+			if i := strings.IndexByte(tok.Data, ';'); i >= 0 && i+1 < len(tok.Data) {
+				w.link = raw
+			} else {
+				w.link = ""
+			}
+		}
+	}
+}
+
+// close the styles and hyperlink, start a new line and re-open them.
+func (w *wrapper) newLine(wrapped bool) {
+	if len(w.styles) > 0 {
+		w.sb.WriteString(ResetSet)
+	}
+	if w.link != "" {
+		w.sb.WriteString(linkCloseSeq)
+	}
+
+	w.sb.WriteByte('\n')
+	w.sb.WriteString(w.indent)
+	w.sb.WriteString(w.link)
+	for _, s := range w.styles {
+		w.sb.WriteString(s)
+	}
+
+	w.setAvail(w.indent)
+	w.lineW, w.spaces = 0, 0
+	w.wrapped = wrapped
+}
+
+// set the available width of the line after the prefix or indent.
+// use the whole width if the prefix is too long.
+func (w *wrapper) setAvail(prefix string) {
+	w.avail = w.width - VisibleWidth(prefix)
+	if w.avail < 1 {
+		w.avail = w.width
+	}
+}
@@ -0,0 +1,102 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/gookit/assert"
+)
+
+func TestVisibleWidth(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq(0, VisibleWidth(""))
+	is.Eq(5, VisibleWidth("hello"))
+	is.Eq(5, VisibleWidth("\x1b[1;32mhello\x1b[0m"))
+	is.Eq(5, VisibleWidth("\x1b]8;;https://example.com\x1b\\hello\x1b]8;;\x1b\\"))
+	is.Eq(4, VisibleWidth("你好"))
+	is.Eq(6, VisibleWidth("\x1b[31m你好\x1b[0mab"))
+	is.Eq(4, VisibleWidth("ｈｉ")) // fullwidth
+	is.Eq(2, VisibleWidth("😀"))
+	is.Eq(2, VisibleWidth("👍🏽"))    // with skin tone modifier
+	is.Eq(2, VisibleWidth("👨‍👩‍👧")) // ZWJ sequence
+	is.Eq(1, VisibleWidth("é"))    // combining mark
+	is.Eq(2, VisibleWidth("a\tb"))
+}
+
+func TestTruncate(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("hello", Truncate("hello", 5, "..."))
+	is.Eq("he...", Truncate("hello world", 5, "..."))
+	is.Eq("hello", Truncate("hello world", 5, ""))
+	is.Eq("..", Truncate("hello world", 2, "..."))
+	is.Eq("", Truncate("hello world", 0, "..."))
+	is.Eq("", Truncate("abc", -1, "..."))
+	is.Eq("", Truncate("", -1, ""))
+	is.Eq("你…", Truncate("你好世界", 4, "…"))
+	// not split the wide char
+	is.Eq("你", Truncate("你好世界", 3, ""))
+	is.Eq("a😀", Truncate("a😀b", 3, ""))
+
+	// styled text, the tail is in the style and reset it
+	is.Eq("\x1b[32mhe...\x1b[0m", Truncate("\x1b[32mhello world\x1b[0m", 5, "..."))
+	is.Eq("\x1b[32mhi\x1b[0m\x1b[1mw…\x1b[0m", Truncate("\x1b[32mhi\x1b[0m\x1b[1mworld\x1b[0m", 4, "…"))
+	is.Eq("\x1b[32mhi\x1b[0mw…", Truncate("\x1b[32mhi\x1b[0mworld\x1b[1mx\x1b[0m", 4, "…"))
+
+	// hyperlink is closed
+	is.Eq("\x1b]8;;https://a.b\x1b\\li…\x1b]8;;\x1b\\", Truncate("\x1b]8;;https://a.b\x1b\\link text\x1b]8;;\x1b\\", 3, "…"))
+}
+
+func TestPad(t *testing.T) {
+	is := assert.New(t)
+
+	s := "\x1b[32mhi\x1b[0m"
+	is.Eq(s+"   ", PadRight(s, 5))
+	is.Eq("   "+s, PadLeft(s, 5))
+	is.Eq(" "+s+"  ", Center(s, 5))
+	is.Eq(s, PadRight(s, 2))
+	is.Eq(s, PadLeft(s, 1))
+	is.Eq(s, Center(s, 0))
+
+	is.Eq("你好 ", PadRight("你好", 5))
+	is.Eq("  你好", PadLeft("你好", 6))
+	is.Eq(" 你好 ", Center("你好", 6))
+}
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("hello", Wrap("hello", 10, WrapOptions{}))
+	is.Eq("hello\nworld", Wrap("hello world", 8, WrapOptions{}))
+	is.Eq("a b c\nd e", Wrap("a b c d e", 5, WrapOptions{}))
+	is.Eq("abcd\nefgh\nij", Wrap("abcdefghij", 4, WrapOptions{}))
+	is.Eq("ab\n\ncd ef", Wrap("ab\n\ncd ef", 5, WrapOptions{}))
+	is.Eq("  ab\ncd", Wrap("  ab cd", 4, WrapOptions{}))
+	is.Eq("你好\n世界", Wrap("你好世界", 5, WrapOptions{}))
+	is.Eq("abc", Wrap("abc", 0, WrapOptions{}))
+
+	// the spaces before the newlines and at the end are kept, removed at the wrap points
+	is.Eq("ab  \ncd ", Wrap("ab  \ncd ", 10, WrapOptions{}))
+	is.Eq("ab\ncd", Wrap("ab   cd", 4, WrapOptions{}))
+	is.Eq("   ", Wrap("   ", 10, WrapOptions{}))
+	is.Eq("\x1b[31mab \x1b[0m", Wrap("\x1b[31mab \x1b[0m", 10, WrapOptions{}))
+
+	// hanging indent
+	is.Eq("ERROR: some\n       error\n       message", Wrap("some error message", 14, WrapOptions{Prefix: "ERROR: "}))
+	is.Eq("ERROR: some\n  error\n  message", Wrap("some error message", 14, WrapOptions{Prefix: "ERROR: ", Indent: "  "}))
+	is.Eq("\x1b[31mERROR:\x1b[0m a b\n       c", Wrap("a b c", 10, WrapOptions{Prefix: "\x1b[31mERROR:\x1b[0m "}))
+	// the prefix or indent is too long, wrap by the whole width
+	is.Eq("ERRORRR: abcd\n         efgh\n         ij", Wrap("abcdefghij", 4, WrapOptions{Prefix: "ERRORRR: "}))
+	is.Eq("ab cd\n     ef", Wrap("ab cd ef", 5, WrapOptions{Indent: "     "}))
+
+	// close and re-open the styles
+	s := Wrap("\x1b[32mhello \x1b[1mgreen\x1b[0m world", 7, WrapOptions{})
+	is.Eq("\x1b[32mhello\x1b[0m\n\x1b[32m\x1b[1mgreen\x1b[0m\nworld", s)
+
+	s = Wrap("\x1b]8;;https://a.b\x1b\\link text\x1b]8;;\x1b\\ end", 5, WrapOptions{})
+	is.Eq("\x1b]8;;https://a.b\x1b\\link\x1b]8;;\x1b\\\n\x1b]8;;https://a.b\x1b\\text\x1b]8;;\x1b\\\nend", s)
+
+	for _, line := range []string{"hello", "green", "world"} {
+		is.Contains(ClearCode(Wrap("\x1b[32mhello \x1b[1mgreen\x1b[0m world", 7, WrapOptions{})), line)
+	}
+}